@@ -26,8 +26,10 @@ func main() {
 	}
 	defer db.Close()
 
-	// Создаем и запускаем checker с 20 workers
-	checker := checker.New(db, 5*time.Minute, 20, cfg.TelegramToken)
+	// Создаем и запускаем checker с 20 workers, список сайтов перечитывается каждые 30 секунд.
+	// Checker также запускает доставку уведомлений из очереди и эскалацию падений.
+	notifier := notifier.New(cfg, db)
+	checker := checker.New(db, 30*time.Second, 20, notifier, cfg.CertExpiryThresholds)
 	ctx, cancel := context.WithCancel(context.Background())
 	defer cancel()
 
@@ -46,14 +48,20 @@ func main() {
 	} else {
 		log.Printf("Telegram notifications disabled (no token provided)")
 	}
+	if cfg.SMTP.Enabled() {
+		log.Printf("Email notifications enabled via %s:%d", cfg.SMTP.Host, cfg.SMTP.Port)
+	}
 	go checker.Start(ctx)
 
-	// Создаем notifier для уведомлений
-	notifier := notifier.New(cfg.TelegramToken, db)
-
 	// Создаем обработчики
 	userHandler := handlers.NewUserHandler(db)
-	siteHandler := handlers.NewSiteHandler(db, notifier)
+	siteHandler := handlers.NewSiteHandler(db, checker)
+	pushHandler := handlers.NewPushHandler(db, checker)
+	incidentHandler := handlers.NewIncidentHandler(db)
+	maintenanceHandler := handlers.NewMaintenanceHandler(db)
+	channelHandler := handlers.NewChannelHandler(db, notifier.Registry())
+	notificationHandler := handlers.NewNotificationHandler(db)
+	escalationHandler := handlers.NewEscalationHandler(db)
 
 	mux := http.NewServeMux()
 
@@ -72,10 +80,42 @@ func main() {
 		userHandler.Login(w, r, cfg.JWTSecret)
 	})
 
+	// Пинги push мониторов авторизуются секретным токеном в URL
+	mux.HandleFunc("POST /api/push/{token}", pushHandler.Push)
+
 	// Защищенные endpoints (требуют JWT)
 	mux.Handle("POST /api/sites", middleware.AuthMiddleware(cfg.JWTSecret)(http.HandlerFunc(siteHandler.AddSite)))
 	mux.Handle("POST /api/sites/bulk", middleware.AuthMiddleware(cfg.JWTSecret)(http.HandlerFunc(siteHandler.BulkAddSites)))
 	mux.Handle("GET /api/sites", middleware.AuthMiddleware(cfg.JWTSecret)(http.HandlerFunc(siteHandler.GetSites)))
+	mux.Handle("GET /api/sites/{id}/checks", middleware.AuthMiddleware(cfg.JWTSecret)(http.HandlerFunc(siteHandler.GetSiteChecks)))
+	mux.Handle("GET /api/sites/{id}/uptime", middleware.AuthMiddleware(cfg.JWTSecret)(http.HandlerFunc(siteHandler.GetSiteUptime)))
+	mux.Handle("GET /api/sites/{id}/latency", middleware.AuthMiddleware(cfg.JWTSecret)(http.HandlerFunc(siteHandler.GetSiteLatency)))
+	mux.Handle("GET /api/sites/{id}/certificate", middleware.AuthMiddleware(cfg.JWTSecret)(http.HandlerFunc(siteHandler.GetSiteCertificate)))
+	mux.Handle("GET /api/uptime", middleware.AuthMiddleware(cfg.JWTSecret)(http.HandlerFunc(siteHandler.GetUptimeSummary)))
+	mux.Handle("GET /api/incidents", middleware.AuthMiddleware(cfg.JWTSecret)(http.HandlerFunc(incidentHandler.GetIncidents)))
+	mux.Handle("GET /api/incidents/{id}", middleware.AuthMiddleware(cfg.JWTSecret)(http.HandlerFunc(incidentHandler.GetIncident)))
+	mux.Handle("POST /api/incidents/{id}/ack", middleware.AuthMiddleware(cfg.JWTSecret)(http.HandlerFunc(incidentHandler.AcknowledgeIncident)))
+	mux.Handle("POST /api/incidents/{id}/notes", middleware.AuthMiddleware(cfg.JWTSecret)(http.HandlerFunc(incidentHandler.AddIncidentNote)))
+	mux.Handle("POST /api/incidents/{id}/resolve", middleware.AuthMiddleware(cfg.JWTSecret)(http.HandlerFunc(incidentHandler.ResolveIncident)))
+	mux.Handle("GET /api/maintenance", middleware.AuthMiddleware(cfg.JWTSecret)(http.HandlerFunc(maintenanceHandler.GetMaintenanceWindows)))
+	mux.Handle("POST /api/maintenance", middleware.AuthMiddleware(cfg.JWTSecret)(http.HandlerFunc(maintenanceHandler.CreateMaintenanceWindow)))
+	mux.Handle("GET /api/maintenance/{id}", middleware.AuthMiddleware(cfg.JWTSecret)(http.HandlerFunc(maintenanceHandler.GetMaintenanceWindow)))
+	mux.Handle("PUT /api/maintenance/{id}", middleware.AuthMiddleware(cfg.JWTSecret)(http.HandlerFunc(maintenanceHandler.UpdateMaintenanceWindow)))
+	mux.Handle("DELETE /api/maintenance/{id}", middleware.AuthMiddleware(cfg.JWTSecret)(http.HandlerFunc(maintenanceHandler.DeleteMaintenanceWindow)))
+	mux.Handle("GET /api/channels", middleware.AuthMiddleware(cfg.JWTSecret)(http.HandlerFunc(channelHandler.GetChannels)))
+	mux.Handle("POST /api/channels", middleware.AuthMiddleware(cfg.JWTSecret)(http.HandlerFunc(channelHandler.CreateChannel)))
+	mux.Handle("GET /api/channels/{id}", middleware.AuthMiddleware(cfg.JWTSecret)(http.HandlerFunc(channelHandler.GetChannel)))
+	mux.Handle("PUT /api/channels/{id}", middleware.AuthMiddleware(cfg.JWTSecret)(http.HandlerFunc(channelHandler.UpdateChannel)))
+	mux.Handle("DELETE /api/channels/{id}", middleware.AuthMiddleware(cfg.JWTSecret)(http.HandlerFunc(channelHandler.DeleteChannel)))
+	mux.Handle("POST /api/channels/{id}/test", middleware.AuthMiddleware(cfg.JWTSecret)(http.HandlerFunc(channelHandler.TestChannel)))
+	mux.Handle("GET /api/notifications/failed", middleware.AuthMiddleware(cfg.JWTSecret)(http.HandlerFunc(notificationHandler.GetFailedNotifications)))
+	mux.Handle("POST /api/notifications/{id}/retry", middleware.AuthMiddleware(cfg.JWTSecret)(http.HandlerFunc(notificationHandler.RetryNotification)))
+	mux.Handle("GET /api/escalation-policies", middleware.AuthMiddleware(cfg.JWTSecret)(http.HandlerFunc(escalationHandler.GetEscalationPolicies)))
+	mux.Handle("POST /api/escalation-policies", middleware.AuthMiddleware(cfg.JWTSecret)(http.HandlerFunc(escalationHandler.CreateEscalationPolicy)))
+	mux.Handle("GET /api/escalation-policies/{id}", middleware.AuthMiddleware(cfg.JWTSecret)(http.HandlerFunc(escalationHandler.GetEscalationPolicy)))
+	mux.Handle("PUT /api/escalation-policies/{id}", middleware.AuthMiddleware(cfg.JWTSecret)(http.HandlerFunc(escalationHandler.UpdateEscalationPolicy)))
+	mux.Handle("DELETE /api/escalation-policies/{id}", middleware.AuthMiddleware(cfg.JWTSecret)(http.HandlerFunc(escalationHandler.DeleteEscalationPolicy)))
+	mux.Handle("PUT /api/sites/{id}", middleware.AuthMiddleware(cfg.JWTSecret)(http.HandlerFunc(siteHandler.UpdateSite)))
 	mux.Handle("DELETE /api/sites/", middleware.AuthMiddleware(cfg.JWTSecret)(http.HandlerFunc(siteHandler.DeleteSite)))
 	mux.Handle("POST /api/sites/bulk-delete", middleware.AuthMiddleware(cfg.JWTSecret)(http.HandlerFunc(siteHandler.BulkDeleteSites)))
 	mux.Handle("POST /api/sites/refresh", middleware.AuthMiddleware(cfg.JWTSecret)(http.HandlerFunc(siteHandler.RefreshSites)))
@@ -0,0 +1,59 @@
+package checker
+
+import (
+	"bytes"
+	"fmt"
+	"regexp"
+	"sync"
+
+	"github.com/aouxes/uptime-monitor/internal/models"
+)
+
+// maxBodyBytes — сколько байт тела ответа читается для проверки содержимого
+const maxBodyBytes = 1 << 20
+
+// keywordRegexps — скомпилированные регулярные выражения keyword по тексту выражения
+var keywordRegexps sync.Map
+
+// compileKeyword компилирует регулярное выражение один раз и переиспользует его в следующих проверках
+func compileKeyword(pattern string) (*regexp.Regexp, error) {
+	if re, ok := keywordRegexps.Load(pattern); ok {
+		return re.(*regexp.Regexp), nil
+	}
+
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		return nil, err
+	}
+	keywordRegexps.Store(pattern, re)
+	return re, nil
+}
+
+// checkKeyword проверяет тело ответа на наличие (отсутствие) ключевого слова или совпадение с regex.
+// Возвращает описание причины сбоя или пустую строку.
+func checkKeyword(site models.Site, body []byte) (string, error) {
+	switch site.KeywordMode {
+	case "":
+		return "", nil
+	case models.KeywordContains:
+		if !bytes.Contains(body, []byte(site.Keyword)) {
+			return fmt.Sprintf("keyword missing: %q", site.Keyword), nil
+		}
+	case models.KeywordNotContains:
+		if bytes.Contains(body, []byte(site.Keyword)) {
+			return fmt.Sprintf("forbidden keyword found: %q", site.Keyword), nil
+		}
+	case models.KeywordRegex:
+		re, err := compileKeyword(site.Keyword)
+		if err != nil {
+			return "", fmt.Errorf("invalid keyword regex: %w", err)
+		}
+		if !re.Match(body) {
+			return fmt.Sprintf("body does not match regex: %q", site.Keyword), nil
+		}
+	default:
+		return "", fmt.Errorf("unsupported keyword_mode %q", site.KeywordMode)
+	}
+
+	return "", nil
+}
@@ -0,0 +1,61 @@
+package checker
+
+import (
+	"testing"
+
+	"github.com/aouxes/uptime-monitor/internal/models"
+)
+
+func TestCheckKeyword(t *testing.T) {
+	body := []byte("<html><body>Welcome! Status: OK</body></html>")
+
+	tests := []struct {
+		name    string
+		mode    string
+		keyword string
+		failed  bool
+	}{
+		{name: "No assertion", mode: "", failed: false},
+		{name: "Contains found", mode: models.KeywordContains, keyword: "Welcome", failed: false},
+		{name: "Contains missing", mode: models.KeywordContains, keyword: "Dashboard", failed: true},
+		{name: "Not contains ok", mode: models.KeywordNotContains, keyword: "Database connection failed", failed: false},
+		{name: "Not contains found", mode: models.KeywordNotContains, keyword: "Status: OK", failed: true},
+		{name: "Regex match", mode: models.KeywordRegex, keyword: `Status:\s+(OK|DEGRADED)`, failed: false},
+		{name: "Regex mismatch", mode: models.KeywordRegex, keyword: `^\{`, failed: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			site := models.Site{Keyword: tt.keyword, KeywordMode: tt.mode}
+
+			reason, err := checkKeyword(site, body)
+			if err != nil {
+				t.Fatalf("Expected no error, but got: %v", err)
+			}
+
+			if tt.failed && reason == "" {
+				t.Error("Expected assertion failure, but got none")
+			}
+
+			if !tt.failed && reason != "" {
+				t.Errorf("Expected no failure, but got: %s", reason)
+			}
+		})
+	}
+}
+
+func TestCompileKeywordReusesRegexp(t *testing.T) {
+	first, err := compileKeyword(`Status:\s+OK`)
+	if err != nil {
+		t.Fatalf("Expected no error, but got: %v", err)
+	}
+
+	second, _ := compileKeyword(`Status:\s+OK`)
+	if first != second {
+		t.Error("Expected the compiled regexp to be reused")
+	}
+
+	if _, err := compileKeyword(`(`); err == nil {
+		t.Error("Expected error for invalid regexp")
+	}
+}
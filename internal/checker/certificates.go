@@ -0,0 +1,147 @@
+package checker
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/aouxes/uptime-monitor/internal/models"
+)
+
+// tlsRootCAs — корневые сертификаты для проверки цепочки (nil — системные)
+var tlsRootCAs *x509.CertPool
+
+// certificateError — ошибка проверки цепочки сертификатов сайта
+type certificateError struct {
+	err error
+}
+
+func (e *certificateError) Error() string {
+	return "certificate invalid: " + e.err.Error()
+}
+
+func (e *certificateError) Unwrap() error {
+	return e.err
+}
+
+// certificateCapture сохраняет цепочку сертификатов из первого TLS рукопожатия
+// и сама проверяет ее, чтобы цепочка была доступна даже для невалидного сертификата
+type certificateCapture struct {
+	mu          sync.Mutex
+	certificate *models.SiteCertificate
+}
+
+func (c *certificateCapture) tlsConfig(hostname string) *tls.Config {
+	return &tls.Config{
+		// Стандартная проверка отключена, вместо нее цепочка проверяется в VerifyConnection
+		InsecureSkipVerify: true,
+		VerifyConnection: func(cs tls.ConnectionState) error {
+			if len(cs.PeerCertificates) == 0 {
+				return &certificateError{err: errors.New("no peer certificates")}
+			}
+
+			c.capture(cs.PeerCertificates)
+
+			dnsName := cs.ServerName
+			if dnsName == "" {
+				dnsName = hostname
+			}
+
+			opts := x509.VerifyOptions{
+				DNSName:       dnsName,
+				Roots:         tlsRootCAs,
+				Intermediates: x509.NewCertPool(),
+			}
+			for _, cert := range cs.PeerCertificates[1:] {
+				opts.Intermediates.AddCert(cert)
+			}
+
+			if _, err := cs.PeerCertificates[0].Verify(opts); err != nil {
+				return &certificateError{err: err}
+			}
+			return nil
+		},
+	}
+}
+
+func (c *certificateCapture) capture(chain []*x509.Certificate) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	// При редиректах сохраняем сертификат исходного хоста
+	if c.certificate != nil {
+		return
+	}
+
+	leaf := chain[0]
+	certificate := &models.SiteCertificate{
+		Subject:   leaf.Subject.String(),
+		Issuer:    leaf.Issuer.String(),
+		SANs:      leaf.DNSNames,
+		NotBefore: leaf.NotBefore,
+		NotAfter:  leaf.NotAfter,
+		CheckedAt: time.Now(),
+	}
+	for _, ip := range leaf.IPAddresses {
+		certificate.SANs = append(certificate.SANs, ip.String())
+	}
+	for _, cert := range chain {
+		certificate.Chain = append(certificate.Chain, models.CertificateInfo{
+			Subject:  cert.Subject.String(),
+			Issuer:   cert.Issuer.String(),
+			NotAfter: cert.NotAfter,
+		})
+	}
+
+	c.certificate = certificate
+}
+
+func (c *certificateCapture) result() *models.SiteCertificate {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.certificate
+}
+
+// certWarningThreshold выбирает порог (в днях), о котором нужно предупредить.
+// lastWarned — последний порог, о котором уже предупреждали (0 — не предупреждали).
+// Возвращает false, если предупреждать не нужно.
+func certWarningThreshold(daysLeft int, thresholds []int, lastWarned int) (int, bool) {
+	sorted := append([]int(nil), thresholds...)
+	sort.Ints(sorted)
+
+	for _, threshold := range sorted {
+		if daysLeft > threshold {
+			continue
+		}
+		if lastWarned != 0 && threshold >= lastWarned {
+			return 0, false
+		}
+		return threshold, true
+	}
+
+	return 0, false
+}
+
+// daysUntil считает полные дни до истечения сертификата
+func daysUntil(notAfter, now time.Time) int {
+	return int(notAfter.Sub(now).Hours() / 24)
+}
+
+// describeCertificateError возвращает понятное описание ошибки сертификата
+func describeCertificateError(err error) (string, bool) {
+	var certErr *certificateError
+	if !errors.As(err, &certErr) {
+		return "", false
+	}
+
+	var invalid x509.CertificateInvalidError
+	if errors.As(err, &invalid) && invalid.Reason == x509.Expired {
+		return fmt.Sprintf("certificate expired: %v", invalid.Detail), true
+	}
+
+	return certErr.Error(), true
+}
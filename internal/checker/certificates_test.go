@@ -0,0 +1,76 @@
+package checker
+
+import (
+	"context"
+	"crypto/x509"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/aouxes/uptime-monitor/internal/models"
+)
+
+func TestCertWarningThreshold(t *testing.T) {
+	thresholds := []int{30, 14, 7, 1}
+
+	tests := []struct {
+		name       string
+		daysLeft   int
+		lastWarned int
+		threshold  int
+		warn       bool
+	}{
+		{name: "Far from expiry", daysLeft: 90, lastWarned: 0, warn: false},
+		{name: "First threshold", daysLeft: 30, lastWarned: 0, threshold: 30, warn: true},
+		{name: "Already warned", daysLeft: 20, lastWarned: 30, warn: false},
+		{name: "Next threshold", daysLeft: 13, lastWarned: 30, threshold: 14, warn: true},
+		{name: "Skipped thresholds", daysLeft: 5, lastWarned: 0, threshold: 7, warn: true},
+		{name: "Last day", daysLeft: 0, lastWarned: 7, threshold: 1, warn: true},
+		{name: "Last day already warned", daysLeft: 0, lastWarned: 1, warn: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			threshold, warn := certWarningThreshold(tt.daysLeft, thresholds, tt.lastWarned)
+
+			if warn != tt.warn || threshold != tt.threshold {
+				t.Errorf("Expected (%d, %v), got (%d, %v)", tt.threshold, tt.warn, threshold, warn)
+			}
+		})
+	}
+}
+
+func TestCheckSiteCapturesCertificate(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer server.Close()
+
+	site := models.NewSite(server.URL, 1)
+
+	// Самоподписанный сертификат тестового сервера не проходит проверку цепочки
+	result, _ := CheckSite(context.Background(), *site)
+	if result.Status != "DOWN" || !strings.HasPrefix(result.Error, "certificate invalid") {
+		t.Errorf("Expected DOWN with certificate error, got %s (%s)", result.Status, result.Error)
+	}
+	if result.Certificate == nil {
+		t.Fatal("Expected certificate to be captured for invalid chain")
+	}
+
+	roots := x509.NewCertPool()
+	roots.AddCert(server.Certificate())
+	tlsRootCAs = roots
+	defer func() { tlsRootCAs = nil }()
+
+	result, err := CheckSite(context.Background(), *site)
+	if err != nil || result.Status != "UP" {
+		t.Fatalf("Expected UP with trusted root, got %s (%v)", result.Status, err)
+	}
+
+	cert := result.Certificate
+	if cert == nil {
+		t.Fatal("Expected certificate to be captured")
+	}
+	if !cert.NotAfter.Equal(server.Certificate().NotAfter) || len(cert.Chain) == 0 {
+		t.Errorf("Unexpected certificate info: %+v", cert)
+	}
+}
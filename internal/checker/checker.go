@@ -2,67 +2,105 @@ package checker
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"log"
+	"sync"
 	"time"
 
+	"github.com/aouxes/uptime-monitor/internal/models"
 	"github.com/aouxes/uptime-monitor/internal/notifier"
 	"github.com/aouxes/uptime-monitor/internal/storage"
 )
 
+// ErrCheckInProgress возвращается, если сайт уже проверяется
+var ErrCheckInProgress = errors.New("site check already in progress")
+
+// ErrPushMonitor возвращается при попытке проверить push монитор вручную
+var ErrPushMonitor = errors.New("push monitors are not checked actively")
+
 type Checker struct {
-	storage    *storage.Storage
-	interval   time.Duration
-	workerPool *WorkerPool
-	notifier   *notifier.Notifier
+	storage      *storage.Storage
+	syncInterval time.Duration
+	workerPool   *WorkerPool
+	scheduler    *Scheduler
+	notifier     *notifier.Notifier
+
+	// pushMu упорядочивает пинги push мониторов и проверку их дедлайнов
+	pushMu sync.Mutex
 }
 
-func New(storage *storage.Storage, interval time.Duration, maxWorkers int, telegramToken string) *Checker {
-	notifier := notifier.New(telegramToken, storage)
+// New создает checker. Список сайтов перечитывается из базы раз в syncInterval,
+// а сами проверки выполняются по индивидуальному интервалу каждого сайта.
+func New(storage *storage.Storage, syncInterval time.Duration, maxWorkers int, notifier *notifier.Notifier, certThresholds []int) *Checker {
 	return &Checker{
-		storage:    storage,
-		interval:   interval,
-		workerPool: NewWorkerPool(storage, maxWorkers, notifier),
-		notifier:   notifier,
+		storage:      storage,
+		syncInterval: syncInterval,
+		workerPool:   NewWorkerPool(storage, maxWorkers, certThresholds),
+		scheduler:    NewScheduler(0.1),
+		notifier:     notifier,
 	}
 }
 
-// CheckAllSites использует worker pool
-func (c *Checker) CheckAllSites(ctx context.Context) error {
-	log.Printf("Starting check for all sites with %d workers...", c.workerPool.maxWorkers)
-
+// SyncSites перечитывает сайты из базы и обновляет очередь планировщика
+func (c *Checker) SyncSites(ctx context.Context) error {
 	sites, err := c.storage.GetAllSites(ctx)
 	if err != nil {
 		return fmt.Errorf("failed to get sites: %w", err)
 	}
 
-	log.Printf("Found %d sites for checking", len(sites))
-
-	// Запускаем проверку асинхронно, не блокируя основной поток
-	go func() {
-		c.workerPool.ProcessSites(ctx, sites)
-		log.Printf("Sites check completed")
-	}()
+	// Push мониторы не проверяются активно — за ними следит sweepPushMonitors
+	active := sites[:0]
+	for _, site := range sites {
+		if site.MonitorType != models.MonitorPush {
+			active = append(active, site)
+		}
+	}
 
+	c.scheduler.Sync(active)
 	return nil
 }
 
-// Start запускает периодическую проверку
-func (c *Checker) Start(ctx context.Context) {
-	ticker := time.NewTicker(c.interval)
-	defer ticker.Stop()
+// CheckNow выполняет внеплановую проверку сайта.
+// Если сайт уже проверяется, возвращает ErrCheckInProgress.
+func (c *Checker) CheckNow(ctx context.Context, site models.Site) (*models.CheckResult, error) {
+	if site.MonitorType == models.MonitorPush {
+		return nil, ErrPushMonitor
+	}
 
-	if err := c.CheckAllSites(ctx); err != nil {
-		log.Printf("Initial check failed: %v", err)
+	if !c.scheduler.Acquire(site) {
+		return nil, ErrCheckInProgress
 	}
 
+	result := c.workerPool.processSite(ctx, &site, 0)
+	c.scheduler.Done(site)
+
+	return result, nil
+}
+
+// Start запускает планировщик, worker'ов, проверку push мониторов,
+// доставку уведомлений из очереди и периодическую синхронизацию списка сайтов
+func (c *Checker) Start(ctx context.Context) {
+	if err := c.SyncSites(ctx); err != nil {
+		log.Printf("Initial sites sync failed: %v", err)
+	}
+
+	jobs := make(chan models.Site)
+	c.workerPool.Start(ctx, jobs, c.scheduler.Done)
+	go c.scheduler.Run(ctx, jobs)
+	go c.runPushSweeper(ctx)
+	go c.notifier.Start(ctx)
+
+	ticker := time.NewTicker(c.syncInterval)
+	defer ticker.Stop()
+
 	for {
 		select {
 		case <-ctx.Done():
 			return
 		case <-ticker.C:
-			if err := c.CheckAllSites(ctx); err != nil {
-				log.Printf("Sites check failed: %v", err)
+			if err := c.SyncSites(ctx); err != nil {
+				log.Printf("Sites sync failed: %v", err)
 			}
 		}
 	}
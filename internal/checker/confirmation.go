@@ -0,0 +1,47 @@
+package checker
+
+import (
+	"time"
+
+	"github.com/aouxes/uptime-monitor/internal/models"
+)
+
+// confirmStatus считает новые счетчики сбоев и успешных проверок подряд
+// и возвращает статус сайта с учетом порогов подтверждения.
+// Пока порог не достигнут, сайт сохраняет прежний статус. После окна обслуживания
+// неподтвержденный сбой не продлевает MAINTENANCE: сайт считается UP, пока сбой не подтвердится.
+func confirmStatus(site models.Site, checkStatus string) (status string, failures, successes int) {
+	failures, successes = site.ConsecutiveFailures, site.ConsecutiveSuccesses
+	if checkStatus == "UP" {
+		successes++
+		failures = 0
+	} else {
+		failures++
+		successes = 0
+	}
+
+	status = checkStatus
+	unavailable := unavailableStatus(site.LastStatus)
+	switch {
+	case checkStatus == "UP" && unavailable && successes < max(site.RecoverThreshold, 1):
+		status = site.LastStatus
+	case checkStatus != "UP" && !unavailable && failures < max(site.FailThreshold, 1):
+		status = site.LastStatus
+		if status == "MAINTENANCE" {
+			status = "UP"
+		}
+	}
+
+	return status, failures, successes
+}
+
+// retryPending сообщает, что у сайта есть неподтвержденный сбой
+// и его нужно перепроверить через RetryIntervalSeconds
+func retryPending(site models.Site) bool {
+	return site.RetryIntervalSeconds > 0 && site.ConsecutiveFailures > 0 && !unavailableStatus(site.LastStatus)
+}
+
+// retryInterval возвращает интервал быстрой повторной проверки
+func retryInterval(site models.Site) time.Duration {
+	return time.Duration(site.RetryIntervalSeconds) * time.Second
+}
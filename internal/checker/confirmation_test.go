@@ -0,0 +1,106 @@
+package checker
+
+import (
+	"testing"
+
+	"github.com/aouxes/uptime-monitor/internal/models"
+)
+
+func TestConfirmStatus(t *testing.T) {
+	site := models.Site{FailThreshold: 3, RecoverThreshold: 2}
+
+	steps := []struct {
+		check  string
+		status string
+	}{
+		{"UP", "UP"},
+		{"DOWN", "UP"}, // 1 сбой
+		{"UP", "UP"},   // счетчик сброшен
+		{"DOWN", "UP"},
+		{"DOWN", "UP"},
+		{"DOWN", "DOWN"}, // 3 сбоя подряд
+		{"DOWN", "DOWN"},
+		{"UP", "DOWN"}, // 1 успешная проверка
+		{"DOWN", "DOWN"},
+		{"UP", "DOWN"},
+		{"UP", "UP"}, // 2 успешные проверки подряд
+	}
+
+	site.LastStatus = "UNKNOWN"
+	for i, step := range steps {
+		status, failures, successes := confirmStatus(site, step.check)
+		if status != step.status {
+			t.Fatalf("step %d: check %s gave status %s, want %s", i, step.check, status, step.status)
+		}
+		site.LastStatus = status
+		site.ConsecutiveFailures = failures
+		site.ConsecutiveSuccesses = successes
+	}
+}
+
+func TestConfirmStatusAfterOutage(t *testing.T) {
+	tests := []struct {
+		name       string
+		lastStatus string
+		check      string
+		want       string
+	}{
+		{name: "Unreachable needs recover threshold", lastStatus: "UNREACHABLE", check: "UP", want: "UNREACHABLE"},
+		{name: "Unreachable fails without threshold", lastStatus: "UNREACHABLE", check: "DOWN", want: "DOWN"},
+		{name: "Maintenance is not carried forward", lastStatus: "MAINTENANCE", check: "DOWN", want: "UP"},
+		{name: "Maintenance recovers immediately", lastStatus: "MAINTENANCE", check: "UP", want: "UP"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			site := models.Site{LastStatus: tt.lastStatus, FailThreshold: 3, RecoverThreshold: 2}
+			if status, _, _ := confirmStatus(site, tt.check); status != tt.want {
+				t.Errorf("status = %s, want %s", status, tt.want)
+			}
+		})
+	}
+}
+
+func TestConfirmStatusDefaults(t *testing.T) {
+	// Нулевые пороги (старые записи) ведут себя как порог 1
+	site := models.Site{LastStatus: "UP"}
+
+	if status, _, _ := confirmStatus(site, "DOWN"); status != "DOWN" {
+		t.Errorf("status = %s, want DOWN", status)
+	}
+
+	site.LastStatus = "DOWN"
+	if status, _, _ := confirmStatus(site, "UP"); status != "UP" {
+		t.Errorf("status = %s, want UP", status)
+	}
+}
+
+func TestValidateThresholds(t *testing.T) {
+	tests := []struct {
+		name    string
+		site    func(site *models.Site)
+		wantErr bool
+	}{
+		{name: "Defaults", site: func(site *models.Site) {}},
+		{name: "Custom", site: func(site *models.Site) {
+			site.FailThreshold = 3
+			site.RecoverThreshold = 2
+			site.RetryIntervalSeconds = 20
+		}},
+		{name: "Zero fail threshold", site: func(site *models.Site) { site.FailThreshold = 0 }, wantErr: true},
+		{name: "Recover threshold too high", site: func(site *models.Site) { site.RecoverThreshold = 11 }, wantErr: true},
+		{name: "Retry interval too short", site: func(site *models.Site) { site.RetryIntervalSeconds = 1 }, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			site := models.NewSite("https://example.com", 1)
+			tt.site(site)
+
+			err := ValidateSite(site)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ValidateSite() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
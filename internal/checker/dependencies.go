@@ -0,0 +1,55 @@
+package checker
+
+import (
+	"context"
+	"fmt"
+	"log"
+
+	"github.com/aouxes/uptime-monitor/internal/models"
+)
+
+// parentUnavailable сообщает, что родительский монитор сайта сейчас DOWN или UNREACHABLE
+func (wp *WorkerPool) parentUnavailable(ctx context.Context, site *models.Site, workerID int) bool {
+	if site.ParentSiteID == 0 {
+		return false
+	}
+
+	parent, err := wp.storage.GetSiteByID(ctx, site.ParentSiteID)
+	if err != nil {
+		log.Printf("Worker %d: Failed to get parent site %d of %s: %v", workerID, site.ParentSiteID, site.URL, err)
+		return false
+	}
+
+	return parent != nil && unavailableStatus(parent.LastStatus)
+}
+
+// unavailableStatus сообщает, что сайт с таким статусом не может обслуживать зависимые от него сайты
+func unavailableStatus(status string) bool {
+	return status == "DOWN" || status == "UNREACHABLE"
+}
+
+// ValidateParentSite проверяет, что родитель сайта — другой сайт того же пользователя
+// и что цепочка родителей не замыкается на сам сайт. sites — все сайты пользователя.
+func ValidateParentSite(site models.Site, sites []models.Site) error {
+	if site.ParentSiteID == 0 {
+		return nil
+	}
+
+	parents := make(map[int]int, len(sites))
+	for _, s := range sites {
+		parents[s.ID] = s.ParentSiteID
+	}
+
+	if _, ok := parents[site.ParentSiteID]; !ok {
+		return fmt.Errorf("Parent site %d not found", site.ParentSiteID)
+	}
+
+	// site.ID == 0 у нового сайта: на него еще никто не ссылается
+	for id, steps := site.ParentSiteID, 0; id != 0 && steps <= len(sites); id, steps = parents[id], steps+1 {
+		if id == site.ID {
+			return fmt.Errorf("parent_site_id creates a dependency cycle")
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,39 @@
+package checker
+
+import (
+	"testing"
+
+	"github.com/aouxes/uptime-monitor/internal/models"
+)
+
+func TestValidateParentSite(t *testing.T) {
+	// 1 <- 2 <- 3: балансировщик, за ним сайт, за ним его API
+	sites := []models.Site{
+		{ID: 1},
+		{ID: 2, ParentSiteID: 1},
+		{ID: 3, ParentSiteID: 2},
+		{ID: 4},
+	}
+
+	tests := []struct {
+		name    string
+		site    models.Site
+		wantErr bool
+	}{
+		{name: "No parent", site: models.Site{ID: 4}},
+		{name: "New site", site: models.Site{ParentSiteID: 3}},
+		{name: "Existing site", site: models.Site{ID: 4, ParentSiteID: 3}},
+		{name: "Unknown parent", site: models.Site{ID: 4, ParentSiteID: 99}, wantErr: true},
+		{name: "Self", site: models.Site{ID: 4, ParentSiteID: 4}, wantErr: true},
+		{name: "Cycle", site: models.Site{ID: 1, ParentSiteID: 3}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateParentSite(tt.site, sites)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ValidateParentSite() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,252 @@
+package checker
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"net"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/aouxes/uptime-monitor/internal/models"
+)
+
+// maxDNSExpected — максимум ожидаемых ответов на один DNS монитор
+const maxDNSExpected = 50
+
+var allowedDNSRecordTypes = map[string]bool{
+	models.DNSRecordA:     true,
+	models.DNSRecordAAAA:  true,
+	models.DNSRecordCNAME: true,
+	models.DNSRecordMX:    true,
+	models.DNSRecordTXT:   true,
+	models.DNSRecordNS:    true,
+}
+
+// CheckDNS запрашивает записи site.DNSRecordType для имени site.URL и сравнивает
+// ответ с ожидаемым набором site.DNSExpected (порядок не важен).
+// NXDOMAIN, SERVFAIL, таймаут и неожиданный ответ считаются DOWN.
+func CheckDNS(ctx context.Context, site models.Site) (*models.CheckResult, error) {
+	name := site.URL
+
+	start := time.Now()
+	result := &models.CheckResult{
+		CheckedAt: start,
+		Status:    "DOWN",
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	answers, err := lookupRecords(ctx, newDNSResolver(site.DNSResolver), site.DNSRecordType, name)
+	result.DNSMs = time.Since(start).Milliseconds()
+	result.ResponseTimeMs = result.DNSMs
+	if err != nil {
+		result.Error = describeDNSError(site.DNSRecordType, name, err)
+		log.Printf("❌ DNS %s %s is DOWN (%s)", site.DNSRecordType, name, result.Error)
+		return result, err
+	}
+
+	if len(site.DNSExpected) > 0 {
+		expected := normalizeDNSAnswers(site.DNSRecordType, site.DNSExpected)
+		if !sameDNSAnswers(answers, expected) {
+			result.Error = fmt.Sprintf("unexpected %s answer: got [%s], expected [%s]",
+				site.DNSRecordType, strings.Join(answers, ", "), strings.Join(expected, ", "))
+			log.Printf("❌ DNS %s %s is DOWN (%s)", site.DNSRecordType, name, result.Error)
+			return result, nil
+		}
+	}
+
+	log.Printf("✅ DNS %s %s is UP (%s, time: %dms)", site.DNSRecordType, name, strings.Join(answers, ", "), result.DNSMs)
+	result.Status = "UP"
+	return result, nil
+}
+
+// newDNSResolver создает резолвер, отправляющий запросы на address ("ip" или "ip:port").
+// Пустой address — системный резолвер.
+func newDNSResolver(address string) *net.Resolver {
+	if address == "" {
+		return &net.Resolver{PreferGo: true}
+	}
+
+	server := dnsResolverAddress(address)
+	return &net.Resolver{
+		PreferGo: true,
+		Dial: func(ctx context.Context, network, _ string) (net.Conn, error) {
+			var dialer net.Dialer
+			return dialer.DialContext(ctx, network, server)
+		},
+	}
+}
+
+// dnsResolverAddress дополняет адрес резолвера портом 53, если порт не указан
+func dnsResolverAddress(address string) string {
+	if _, _, err := net.SplitHostPort(address); err == nil {
+		return address
+	}
+	return net.JoinHostPort(strings.Trim(address, "[]"), "53")
+}
+
+// lookupRecords возвращает нормализованные ответы на запрос записей recordType
+func lookupRecords(ctx context.Context, resolver *net.Resolver, recordType, name string) ([]string, error) {
+	var answers []string
+
+	switch recordType {
+	case models.DNSRecordA, models.DNSRecordAAAA:
+		network := "ip4"
+		if recordType == models.DNSRecordAAAA {
+			network = "ip6"
+		}
+		ips, err := resolver.LookupIP(ctx, network, name)
+		if err != nil {
+			return nil, err
+		}
+		for _, ip := range ips {
+			answers = append(answers, ip.String())
+		}
+	case models.DNSRecordCNAME:
+		// Возвращает конечное каноническое имя после всей цепочки CNAME
+		cname, err := resolver.LookupCNAME(ctx, name)
+		if err != nil {
+			return nil, err
+		}
+		answers = append(answers, cname)
+	case models.DNSRecordMX:
+		records, err := resolver.LookupMX(ctx, name)
+		if err != nil {
+			return nil, err
+		}
+		for _, mx := range records {
+			answers = append(answers, mx.Host)
+		}
+	case models.DNSRecordTXT:
+		records, err := resolver.LookupTXT(ctx, name)
+		if err != nil {
+			return nil, err
+		}
+		answers = append(answers, records...)
+	case models.DNSRecordNS:
+		records, err := resolver.LookupNS(ctx, name)
+		if err != nil {
+			return nil, err
+		}
+		for _, ns := range records {
+			answers = append(answers, ns.Host)
+		}
+	default:
+		return nil, fmt.Errorf("unsupported DNS record type %q", recordType)
+	}
+
+	return normalizeDNSAnswers(recordType, answers), nil
+}
+
+// normalizeDNSAnswers приводит ответы к виду для сравнения: IP адреса — в каноническую
+// форму, имена — в нижний регистр без завершающей точки. Результат отсортирован.
+func normalizeDNSAnswers(recordType string, answers []string) []string {
+	normalized := make([]string, 0, len(answers))
+	for _, answer := range answers {
+		switch recordType {
+		case models.DNSRecordA, models.DNSRecordAAAA:
+			answer = strings.TrimSpace(answer)
+			if ip := net.ParseIP(answer); ip != nil {
+				answer = ip.String()
+			}
+		case models.DNSRecordTXT:
+		default:
+			answer = strings.TrimSuffix(strings.ToLower(strings.TrimSpace(answer)), ".")
+		}
+		normalized = append(normalized, answer)
+	}
+
+	sort.Strings(normalized)
+	return normalized
+}
+
+// sameDNSAnswers сравнивает наборы ответов без учета порядка и дубликатов
+func sameDNSAnswers(actual, expected []string) bool {
+	actualSet := make(map[string]bool, len(actual))
+	for _, answer := range actual {
+		actualSet[answer] = true
+	}
+	expectedSet := make(map[string]bool, len(expected))
+	for _, answer := range expected {
+		expectedSet[answer] = true
+	}
+
+	if len(actualSet) != len(expectedSet) {
+		return false
+	}
+	for answer := range expectedSet {
+		if !actualSet[answer] {
+			return false
+		}
+	}
+	return true
+}
+
+// describeDNSError возвращает понятное описание ошибки DNS запроса
+func describeDNSError(recordType, name string, err error) string {
+	var dnsErr *net.DNSError
+	if !errors.As(err, &dnsErr) {
+		return err.Error()
+	}
+
+	switch {
+	case dnsErr.IsTimeout:
+		return fmt.Sprintf("DNS timeout resolving %s %s", recordType, name)
+	case dnsErr.IsNotFound:
+		return fmt.Sprintf("NXDOMAIN: no %s records for %s", recordType, name)
+	case dnsErr.Err == "server misbehaving":
+		return fmt.Sprintf("SERVFAIL resolving %s %s", recordType, name)
+	}
+
+	return dnsErr.Error()
+}
+
+func validateDNSSite(site *models.Site) error {
+	name := strings.TrimSuffix(site.URL, ".")
+	if name == "" || len(name) > 253 || strings.ContainsAny(name, " /:") {
+		return fmt.Errorf("Invalid domain name")
+	}
+
+	if !allowedDNSRecordTypes[site.DNSRecordType] {
+		return fmt.Errorf("unsupported dns_record_type %q", site.DNSRecordType)
+	}
+
+	if site.DNSResolver != "" {
+		host, port, err := net.SplitHostPort(dnsResolverAddress(site.DNSResolver))
+		if err != nil || net.ParseIP(host) == nil {
+			return fmt.Errorf("Invalid dns_resolver %q, expected IP address with optional port", site.DNSResolver)
+		}
+		if n, err := strconv.Atoi(port); err != nil || n < 1 || n > 65535 {
+			return fmt.Errorf("Invalid dns_resolver port %q", port)
+		}
+	}
+
+	if len(site.DNSExpected) > maxDNSExpected {
+		return fmt.Errorf("too many dns_expected values, maximum %d", maxDNSExpected)
+	}
+
+	for _, answer := range site.DNSExpected {
+		answer = strings.TrimSpace(answer)
+		if answer == "" {
+			return fmt.Errorf("dns_expected values must not be empty")
+		}
+
+		switch site.DNSRecordType {
+		case models.DNSRecordA:
+			if ip := net.ParseIP(answer); ip == nil || ip.To4() == nil {
+				return fmt.Errorf("dns_expected value %q is not an IPv4 address", answer)
+			}
+		case models.DNSRecordAAAA:
+			if ip := net.ParseIP(answer); ip == nil || ip.To4() != nil {
+				return fmt.Errorf("dns_expected value %q is not an IPv6 address", answer)
+			}
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,259 @@
+package checker
+
+import (
+	"context"
+	"encoding/binary"
+	"fmt"
+	"net"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/aouxes/uptime-monitor/internal/models"
+)
+
+// Коды DNS, используемые заглушкой
+const (
+	stubTypeA     = 1
+	stubTypeNS    = 2
+	stubTypeCNAME = 5
+	stubTypeMX    = 15
+	stubTypeTXT   = 16
+	stubTypeAAAA  = 28
+
+	stubRcodeServFail = 2
+	stubRcodeNXDomain = 3
+)
+
+// stubRecord — запись в ответе заглушки
+type stubRecord struct {
+	name  string
+	rtype uint16
+	data  []byte
+}
+
+// stubAnswer — ответ заглушки на вопрос (имя, тип)
+type stubAnswer struct {
+	rcode   int
+	records []stubRecord
+	silent  bool // не отвечать, чтобы вызвать таймаут
+}
+
+// startStubDNSServer запускает UDP DNS сервер, отвечающий по таблице zone.
+// Ключ таблицы — "имя. ТИП" в нижнем регистре, например "example.test. 1".
+// На отсутствующие в таблице вопросы сервер отвечает NOERROR без записей.
+func startStubDNSServer(t *testing.T, zone map[string]stubAnswer) string {
+	t.Helper()
+
+	conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listen: %v", err)
+	}
+	t.Cleanup(func() { conn.Close() })
+
+	go func() {
+		buf := make([]byte, 1500)
+		for {
+			n, addr, err := conn.ReadFrom(buf)
+			if err != nil {
+				return
+			}
+			query := append([]byte(nil), buf[:n]...)
+
+			name, qtype, questionEnd, ok := parseStubQuestion(query)
+			if !ok {
+				continue
+			}
+
+			answer := zone[stubKey(name, qtype)]
+			if answer.silent {
+				continue
+			}
+
+			conn.WriteTo(buildStubResponse(query[:questionEnd], answer), addr)
+		}
+	}()
+
+	return conn.LocalAddr().String()
+}
+
+func stubKey(name string, qtype uint16) string {
+	return fmt.Sprintf("%s %d", strings.ToLower(name), qtype)
+}
+
+// parseStubQuestion разбирает первый вопрос запроса
+func parseStubQuestion(msg []byte) (string, uint16, int, bool) {
+	if len(msg) < 12 {
+		return "", 0, 0, false
+	}
+
+	var labels []string
+	offset := 12
+	for {
+		if offset >= len(msg) {
+			return "", 0, 0, false
+		}
+		length := int(msg[offset])
+		offset++
+		if length == 0 {
+			break
+		}
+		if offset+length > len(msg) {
+			return "", 0, 0, false
+		}
+		labels = append(labels, string(msg[offset:offset+length]))
+		offset += length
+	}
+
+	if offset+4 > len(msg) {
+		return "", 0, 0, false
+	}
+	qtype := binary.BigEndian.Uint16(msg[offset:])
+	return strings.Join(labels, ".") + ".", qtype, offset + 4, true
+}
+
+// buildStubResponse собирает ответ из заголовка и вопроса запроса
+func buildStubResponse(question []byte, answer stubAnswer) []byte {
+	resp := append([]byte(nil), question...)
+
+	// QR, AA, RD из запроса, RA и код ответа
+	flags := uint16(0x8400) | binary.BigEndian.Uint16(question[2:])&0x0100 | 0x0080 | uint16(answer.rcode)
+	binary.BigEndian.PutUint16(resp[2:], flags)
+	binary.BigEndian.PutUint16(resp[4:], 1)
+	binary.BigEndian.PutUint16(resp[6:], uint16(len(answer.records)))
+	binary.BigEndian.PutUint16(resp[8:], 0)
+	binary.BigEndian.PutUint16(resp[10:], 0)
+
+	for _, record := range answer.records {
+		resp = append(resp, encodeStubName(record.name)...)
+		resp = binary.BigEndian.AppendUint16(resp, record.rtype)
+		resp = binary.BigEndian.AppendUint16(resp, 1) // IN
+		resp = binary.BigEndian.AppendUint32(resp, 60)
+		resp = binary.BigEndian.AppendUint16(resp, uint16(len(record.data)))
+		resp = append(resp, record.data...)
+	}
+
+	return resp
+}
+
+func encodeStubName(name string) []byte {
+	var out []byte
+	for _, label := range strings.Split(strings.TrimSuffix(name, "."), ".") {
+		out = append(out, byte(len(label)))
+		out = append(out, label...)
+	}
+	return append(out, 0)
+}
+
+func stubMX(preference uint16, host string) []byte {
+	return append(binary.BigEndian.AppendUint16(nil, preference), encodeStubName(host)...)
+}
+
+func stubTXT(text string) []byte {
+	return append([]byte{byte(len(text))}, text...)
+}
+
+func TestCheckDNS(t *testing.T) {
+	zone := map[string]stubAnswer{
+		stubKey("example.test.", stubTypeA): {records: []stubRecord{
+			{name: "example.test.", rtype: stubTypeA, data: []byte{192, 0, 2, 10}},
+			{name: "example.test.", rtype: stubTypeA, data: []byte{192, 0, 2, 11}},
+		}},
+		stubKey("example.test.", stubTypeAAAA): {records: []stubRecord{
+			{name: "example.test.", rtype: stubTypeAAAA, data: net.ParseIP("2001:db8::1")},
+		}},
+		stubKey("www.example.test.", stubTypeA): {records: []stubRecord{
+			{name: "www.example.test.", rtype: stubTypeCNAME, data: encodeStubName("cdn.example.net.")},
+			{name: "cdn.example.net.", rtype: stubTypeA, data: []byte{198, 51, 100, 7}},
+		}},
+		stubKey("example.test.", stubTypeMX): {records: []stubRecord{
+			{name: "example.test.", rtype: stubTypeMX, data: stubMX(10, "mx1.example.test.")},
+			{name: "example.test.", rtype: stubTypeMX, data: stubMX(20, "mx2.example.test.")},
+		}},
+		stubKey("example.test.", stubTypeTXT): {records: []stubRecord{
+			{name: "example.test.", rtype: stubTypeTXT, data: stubTXT("v=spf1 -all")},
+		}},
+		stubKey("example.test.", stubTypeNS): {records: []stubRecord{
+			{name: "example.test.", rtype: stubTypeNS, data: encodeStubName("ns1.example.test.")},
+		}},
+		stubKey("missing.example.test.", stubTypeA): {rcode: stubRcodeNXDomain},
+		stubKey("broken.example.test.", stubTypeA):  {rcode: stubRcodeServFail},
+		stubKey("slow.example.test.", stubTypeA):    {silent: true},
+	}
+	resolver := startStubDNSServer(t, zone)
+
+	tests := []struct {
+		name       string
+		host       string
+		recordType string
+		expected   []string
+		status     string
+		errContain string
+	}{
+		{name: "A any answer", host: "example.test.", recordType: models.DNSRecordA, status: "UP"},
+		{name: "A expected set in any order", host: "example.test.", recordType: models.DNSRecordA, expected: []string{"192.0.2.11", "192.0.2.10"}, status: "UP"},
+		{name: "A unexpected answer", host: "example.test.", recordType: models.DNSRecordA, expected: []string{"192.0.2.10"}, status: "DOWN", errContain: "unexpected A answer"},
+		{name: "AAAA", host: "example.test.", recordType: models.DNSRecordAAAA, expected: []string{"2001:DB8:0::1"}, status: "UP"},
+		{name: "CNAME", host: "www.example.test.", recordType: models.DNSRecordCNAME, expected: []string{"CDN.example.net"}, status: "UP"},
+		{name: "MX", host: "example.test.", recordType: models.DNSRecordMX, expected: []string{"mx1.example.test", "mx2.example.test."}, status: "UP"},
+		{name: "TXT", host: "example.test.", recordType: models.DNSRecordTXT, expected: []string{"v=spf1 -all"}, status: "UP"},
+		{name: "NS hijacked", host: "example.test.", recordType: models.DNSRecordNS, expected: []string{"ns.attacker.test"}, status: "DOWN", errContain: "got [ns1.example.test]"},
+		{name: "NXDOMAIN", host: "missing.example.test.", recordType: models.DNSRecordA, status: "DOWN", errContain: "NXDOMAIN"},
+		{name: "SERVFAIL", host: "broken.example.test.", recordType: models.DNSRecordA, status: "DOWN", errContain: "SERVFAIL"},
+		{name: "Timeout", host: "slow.example.test.", recordType: models.DNSRecordA, status: "DOWN", errContain: "timeout"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			site := models.NewSite(tt.host, 1)
+			site.MonitorType = models.MonitorDNS
+			site.DNSRecordType = tt.recordType
+			site.DNSResolver = resolver
+			site.DNSExpected = tt.expected
+
+			ctx, cancel := context.WithTimeout(context.Background(), 500*time.Millisecond)
+			defer cancel()
+
+			result, _ := RunCheck(ctx, *site)
+			if result.Status != tt.status {
+				t.Fatalf("status = %s, want %s (error: %s)", result.Status, tt.status, result.Error)
+			}
+			if !strings.Contains(result.Error, tt.errContain) {
+				t.Errorf("error = %q, want it to contain %q", result.Error, tt.errContain)
+			}
+		})
+	}
+}
+
+func TestValidateDNSSite(t *testing.T) {
+	tests := []struct {
+		name    string
+		site    func(site *models.Site)
+		wantErr bool
+	}{
+		{name: "Defaults", site: func(site *models.Site) {}},
+		{name: "Resolver with port", site: func(site *models.Site) { site.DNSResolver = "1.1.1.1:5353" }},
+		{name: "IPv6 resolver", site: func(site *models.Site) { site.DNSResolver = "2606:4700:4700::1111" }},
+		{name: "Hostname resolver", site: func(site *models.Site) { site.DNSResolver = "dns.google" }, wantErr: true},
+		{name: "URL instead of name", site: func(site *models.Site) { site.URL = "https://example.com" }, wantErr: true},
+		{name: "Unsupported record type", site: func(site *models.Site) { site.DNSRecordType = "SRV" }, wantErr: true},
+		{name: "IPv6 in A set", site: func(site *models.Site) { site.DNSExpected = []string{"2001:db8::1"} }, wantErr: true},
+		{name: "MX hosts", site: func(site *models.Site) {
+			site.DNSRecordType = models.DNSRecordMX
+			site.DNSExpected = []string{"mx1.example.com"}
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			site := models.NewSite("example.com", 1)
+			site.MonitorType = models.MonitorDNS
+			tt.site(site)
+
+			err := ValidateSite(site)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ValidateSite() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,66 @@
+package checker
+
+import (
+	"context"
+	"log"
+
+	"github.com/aouxes/uptime-monitor/internal/models"
+)
+
+// incidentAction — что нужно сделать с инцидентом сайта после проверки
+type incidentAction int
+
+const (
+	incidentNone incidentAction = iota
+	incidentOpen
+	incidentUpdate
+	incidentResolve
+)
+
+// incidentTransition определяет действие с инцидентом по смене подтвержденного статуса.
+// Окно обслуживания и недоступность родителя не закрывают открытый инцидент:
+// его закрывает только восстановление сайта.
+func incidentTransition(oldStatus, newStatus string) incidentAction {
+	switch {
+	case newStatus == "DOWN" && oldStatus != "DOWN":
+		return incidentOpen
+	case newStatus == "DOWN":
+		return incidentUpdate
+	case newStatus == "UP" && (unavailableStatus(oldStatus) || oldStatus == "MAINTENANCE"):
+		return incidentResolve
+	}
+	return incidentNone
+}
+
+// trackIncident открывает, дополняет или закрывает инцидент сайта по смене статуса oldStatus -> newStatus.
+// Возвращает ID инцидента, открытого или закрытого этой проверкой (0, если таких нет).
+func (wp *WorkerPool) trackIncident(ctx context.Context, site *models.Site, oldStatus, newStatus string, result *models.CheckResult, workerID int) int {
+	switch incidentTransition(oldStatus, newStatus) {
+	case incidentOpen:
+		incidentID, err := wp.storage.OpenIncident(ctx, site.ID, result.CheckedAt, result.Error)
+		if err != nil {
+			log.Printf("Worker %d: Failed to open incident for site %s: %v", workerID, site.URL, err)
+			return 0
+		}
+		if incidentID != 0 {
+			log.Printf("Worker %d: Opened incident %d for site %s", workerID, incidentID, site.URL)
+		}
+		return incidentID
+	case incidentUpdate:
+		if err := wp.storage.RecordIncidentFailure(ctx, site.ID, result.CheckedAt, result.Error); err != nil {
+			log.Printf("Worker %d: Failed to update incident for site %s: %v", workerID, site.URL, err)
+		}
+	case incidentResolve:
+		incidentID, err := wp.storage.ResolveIncident(ctx, site.ID, result.CheckedAt, "site is "+newStatus)
+		if err != nil {
+			log.Printf("Worker %d: Failed to resolve incident for site %s: %v", workerID, site.URL, err)
+			return 0
+		}
+		if incidentID != 0 {
+			log.Printf("Worker %d: Resolved incident %d for site %s", workerID, incidentID, site.URL)
+		}
+		return incidentID
+	}
+
+	return 0
+}
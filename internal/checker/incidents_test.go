@@ -0,0 +1,31 @@
+package checker
+
+import "testing"
+
+func TestIncidentTransition(t *testing.T) {
+	tests := []struct {
+		oldStatus string
+		newStatus string
+		want      incidentAction
+	}{
+		{"UNKNOWN", "UP", incidentNone},
+		{"UP", "UP", incidentNone},
+		{"UP", "DOWN", incidentOpen},
+		{"UNKNOWN", "DOWN", incidentOpen},
+		{"DOWN", "DOWN", incidentUpdate},
+		{"DOWN", "UP", incidentResolve},
+		{"DOWN", "MAINTENANCE", incidentNone},
+		{"UP", "MAINTENANCE", incidentNone},
+		{"MAINTENANCE", "DOWN", incidentOpen},
+		{"MAINTENANCE", "UP", incidentResolve},
+		{"DOWN", "UNREACHABLE", incidentNone},
+		{"UNREACHABLE", "DOWN", incidentOpen},
+		{"UNREACHABLE", "UP", incidentResolve},
+	}
+
+	for _, tt := range tests {
+		if got := incidentTransition(tt.oldStatus, tt.newStatus); got != tt.want {
+			t.Errorf("incidentTransition(%s, %s) = %v, want %v", tt.oldStatus, tt.newStatus, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,257 @@
+package checker
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/aouxes/uptime-monitor/internal/models"
+)
+
+// maxJSONAssertions — максимум JSON проверок на один сайт
+const maxJSONAssertions = 20
+
+// pathStep — один шаг JSONPath: ключ объекта или индекс массива
+type pathStep struct {
+	key   string
+	index int
+	isKey bool
+}
+
+// parseJSONPath разбирает упрощенный JSONPath вида "$.checks[0].status" или "db"
+func parseJSONPath(path string) ([]pathStep, error) {
+	rest := strings.TrimSpace(path)
+	rest = strings.TrimPrefix(rest, "$")
+	rest = strings.TrimPrefix(rest, ".")
+
+	var steps []pathStep
+	for rest != "" {
+		if rest[0] == '[' {
+			end := strings.IndexByte(rest, ']')
+			if end < 0 {
+				return nil, fmt.Errorf("invalid path %q: unclosed bracket", path)
+			}
+			index, err := strconv.Atoi(rest[1:end])
+			if err != nil || index < 0 {
+				return nil, fmt.Errorf("invalid path %q: bad index %q", path, rest[1:end])
+			}
+			steps = append(steps, pathStep{index: index})
+			rest = strings.TrimPrefix(rest[end+1:], ".")
+			continue
+		}
+
+		end := strings.IndexAny(rest, ".[")
+		if end < 0 {
+			end = len(rest)
+		}
+		if end == 0 {
+			return nil, fmt.Errorf("invalid path %q: empty key", path)
+		}
+		steps = append(steps, pathStep{key: rest[:end], isKey: true})
+		rest = strings.TrimPrefix(rest[end:], ".")
+	}
+
+	return steps, nil
+}
+
+// selectJSONPath возвращает значение по пути и признак того, что оно найдено
+func selectJSONPath(doc interface{}, steps []pathStep) (interface{}, bool) {
+	current := doc
+	for _, step := range steps {
+		if step.isKey {
+			object, ok := current.(map[string]interface{})
+			if !ok {
+				return nil, false
+			}
+			current, ok = object[step.key]
+			if !ok {
+				return nil, false
+			}
+			continue
+		}
+
+		array, ok := current.([]interface{})
+		if !ok || step.index >= len(array) {
+			return nil, false
+		}
+		current = array[step.index]
+	}
+
+	return current, true
+}
+
+// checkJSONAssertions проверяет тело ответа по JSON проверкам сайта.
+// Возвращает описание каждой не прошедшей проверки.
+func checkJSONAssertions(assertions []models.JSONAssertion, body []byte) []string {
+	if len(assertions) == 0 {
+		return nil
+	}
+
+	var doc interface{}
+	if err := json.Unmarshal(body, &doc); err != nil {
+		return []string{fmt.Sprintf("response is not valid JSON: %v", err)}
+	}
+
+	var failures []string
+	for _, assertion := range assertions {
+		if reason := evaluateJSONAssertion(assertion, doc); reason != "" {
+			failures = append(failures, reason)
+		}
+	}
+
+	return failures
+}
+
+func evaluateJSONAssertion(assertion models.JSONAssertion, doc interface{}) string {
+	steps, err := parseJSONPath(assertion.Path)
+	if err != nil {
+		return err.Error()
+	}
+
+	actual, found := selectJSONPath(doc, steps)
+
+	switch assertion.Op {
+	case models.JSONOpExists:
+		if !found {
+			return fmt.Sprintf("%s: expected to exist", assertion.Path)
+		}
+		return ""
+	case models.JSONOpNotExists:
+		if found {
+			return fmt.Sprintf("%s: expected not to exist, got %s", assertion.Path, formatJSONValue(actual))
+		}
+		return ""
+	}
+
+	if !found {
+		return fmt.Sprintf("%s: not found", assertion.Path)
+	}
+
+	switch assertion.Op {
+	case models.JSONOpEquals:
+		if !jsonValuesEqual(actual, assertion.Value) {
+			return fmt.Sprintf("%s: expected %s, got %s", assertion.Path, formatJSONValue(assertion.Value), formatJSONValue(actual))
+		}
+	case models.JSONOpNotEquals:
+		if jsonValuesEqual(actual, assertion.Value) {
+			return fmt.Sprintf("%s: expected not %s", assertion.Path, formatJSONValue(assertion.Value))
+		}
+	case models.JSONOpContains:
+		if !jsonContains(actual, assertion.Value) {
+			return fmt.Sprintf("%s: expected to contain %s, got %s", assertion.Path, formatJSONValue(assertion.Value), formatJSONValue(actual))
+		}
+	case models.JSONOpGreater, models.JSONOpGreaterOrEqual, models.JSONOpLess, models.JSONOpLessOrEqual:
+		actualNumber, ok := toNumber(actual)
+		if !ok {
+			return fmt.Sprintf("%s: expected a number, got %s", assertion.Path, formatJSONValue(actual))
+		}
+		expectedNumber, _ := toNumber(assertion.Value)
+		if !compareNumbers(assertion.Op, actualNumber, expectedNumber) {
+			return fmt.Sprintf("%s: expected %s %s, got %s", assertion.Path, assertion.Op, formatJSONValue(assertion.Value), formatJSONValue(actual))
+		}
+	default:
+		return fmt.Sprintf("%s: unsupported op %q", assertion.Path, assertion.Op)
+	}
+
+	return ""
+}
+
+func jsonValuesEqual(actual, expected interface{}) bool {
+	if a, ok := actual.(float64); ok {
+		if e, ok := toNumber(expected); ok {
+			return a == e
+		}
+	}
+	return reflect.DeepEqual(actual, expected)
+}
+
+func jsonContains(actual, expected interface{}) bool {
+	switch value := actual.(type) {
+	case string:
+		s, ok := expected.(string)
+		return ok && strings.Contains(value, s)
+	case []interface{}:
+		for _, item := range value {
+			if jsonValuesEqual(item, expected) {
+				return true
+			}
+		}
+	case map[string]interface{}:
+		key, ok := expected.(string)
+		if ok {
+			_, exists := value[key]
+			return exists
+		}
+	}
+	return false
+}
+
+func compareNumbers(op string, actual, expected float64) bool {
+	switch op {
+	case models.JSONOpGreater:
+		return actual > expected
+	case models.JSONOpGreaterOrEqual:
+		return actual >= expected
+	case models.JSONOpLess:
+		return actual < expected
+	case models.JSONOpLessOrEqual:
+		return actual <= expected
+	}
+	return false
+}
+
+// toNumber приводит число или числовую строку к float64
+func toNumber(value interface{}) (float64, bool) {
+	switch v := value.(type) {
+	case float64:
+		return v, true
+	case int:
+		return float64(v), true
+	case string:
+		f, err := strconv.ParseFloat(strings.TrimSpace(v), 64)
+		return f, err == nil
+	}
+	return 0, false
+}
+
+func formatJSONValue(value interface{}) string {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return fmt.Sprint(value)
+	}
+	if len(data) > 100 {
+		return string(data[:100]) + "..."
+	}
+	return string(data)
+}
+
+// validateJSONAssertions проверяет корректность JSON проверок сайта
+func validateJSONAssertions(assertions []models.JSONAssertion) error {
+	if len(assertions) > maxJSONAssertions {
+		return fmt.Errorf("too many json_assertions, maximum %d", maxJSONAssertions)
+	}
+
+	for _, assertion := range assertions {
+		if _, err := parseJSONPath(assertion.Path); err != nil {
+			return err
+		}
+
+		switch assertion.Op {
+		case models.JSONOpExists, models.JSONOpNotExists, models.JSONOpEquals, models.JSONOpNotEquals:
+		case models.JSONOpContains:
+			if assertion.Value == nil {
+				return fmt.Errorf("%s: value is required for op %q", assertion.Path, assertion.Op)
+			}
+		case models.JSONOpGreater, models.JSONOpGreaterOrEqual, models.JSONOpLess, models.JSONOpLessOrEqual:
+			if _, ok := toNumber(assertion.Value); !ok {
+				return fmt.Errorf("%s: numeric value is required for op %q", assertion.Path, assertion.Op)
+			}
+		default:
+			return fmt.Errorf("%s: unsupported op %q", assertion.Path, assertion.Op)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,92 @@
+package checker
+
+import (
+	"testing"
+
+	"github.com/aouxes/uptime-monitor/internal/models"
+)
+
+func TestCheckJSONAssertions(t *testing.T) {
+	body := []byte(`{
+		"status": "ok",
+		"db": "up",
+		"queue": {"size": 42, "workers": "3"},
+		"checks": [{"name": "cache", "status": "degraded"}],
+		"tags": ["eu", "primary"]
+	}`)
+
+	tests := []struct {
+		name      string
+		assertion models.JSONAssertion
+		failed    bool
+	}{
+		{name: "Equals string", assertion: models.JSONAssertion{Path: "$.status", Op: "equals", Value: "ok"}},
+		{name: "Equals without root", assertion: models.JSONAssertion{Path: "db", Op: "equals", Value: "up"}},
+		{name: "Equals mismatch", assertion: models.JSONAssertion{Path: "$.db", Op: "equals", Value: "down"}, failed: true},
+		{name: "Not equals", assertion: models.JSONAssertion{Path: "$.checks[0].status", Op: "not_equals", Value: "ok"}},
+		{name: "Equals number", assertion: models.JSONAssertion{Path: "$.queue.size", Op: "equals", Value: float64(42)}},
+		{name: "Less than", assertion: models.JSONAssertion{Path: "$.queue.size", Op: "lt", Value: float64(1000)}},
+		{name: "Greater than fails", assertion: models.JSONAssertion{Path: "$.queue.size", Op: "gt", Value: float64(100)}, failed: true},
+		{name: "Numeric string", assertion: models.JSONAssertion{Path: "$.queue.workers", Op: "gte", Value: "3"}},
+		{name: "Contains in array", assertion: models.JSONAssertion{Path: "$.tags", Op: "contains", Value: "primary"}},
+		{name: "Contains in string", assertion: models.JSONAssertion{Path: "$.checks[0].status", Op: "contains", Value: "grad"}},
+		{name: "Exists", assertion: models.JSONAssertion{Path: "$.checks[0].name", Op: "exists"}},
+		{name: "Missing path", assertion: models.JSONAssertion{Path: "$.checks[3].name", Op: "exists"}, failed: true},
+		{name: "Not exists", assertion: models.JSONAssertion{Path: "$.error", Op: "not_exists"}},
+		{name: "Not found for equals", assertion: models.JSONAssertion{Path: "$.missing", Op: "equals", Value: "x"}, failed: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			failures := checkJSONAssertions([]models.JSONAssertion{tt.assertion}, body)
+
+			if tt.failed && len(failures) == 0 {
+				t.Error("Expected assertion failure, but got none")
+			}
+
+			if !tt.failed && len(failures) > 0 {
+				t.Errorf("Expected no failures, but got: %v", failures)
+			}
+		})
+	}
+}
+
+func TestCheckJSONAssertionsReportsEachFailure(t *testing.T) {
+	assertions := []models.JSONAssertion{
+		{Path: "$.status", Op: "equals", Value: "ok"},
+		{Path: "$.db", Op: "equals", Value: "up"},
+		{Path: "$.cache", Op: "equals", Value: "up"},
+	}
+
+	failures := checkJSONAssertions(assertions, []byte(`{"status": "ok", "db": "down", "cache": "down"}`))
+	if len(failures) != 2 {
+		t.Errorf("Expected 2 failures, got %v", failures)
+	}
+
+	failures = checkJSONAssertions(assertions, []byte(`<html>not json</html>`))
+	if len(failures) != 1 {
+		t.Errorf("Expected invalid JSON failure, got %v", failures)
+	}
+}
+
+func TestValidateJSONAssertions(t *testing.T) {
+	valid := []models.JSONAssertion{
+		{Path: "$.status", Op: "equals", Value: "ok"},
+		{Path: "$.items[0]", Op: "exists"},
+		{Path: "$.latency", Op: "lte", Value: float64(250)},
+	}
+	if err := validateJSONAssertions(valid); err != nil {
+		t.Errorf("Expected no error, but got: %v", err)
+	}
+
+	invalid := [][]models.JSONAssertion{
+		{{Path: "$.items[x]", Op: "exists"}},
+		{{Path: "$.status", Op: "matches", Value: "ok"}},
+		{{Path: "$.latency", Op: "gt", Value: "fast"}},
+	}
+	for _, assertions := range invalid {
+		if err := validateJSONAssertions(assertions); err == nil {
+			t.Errorf("Expected validation error for %+v", assertions)
+		}
+	}
+}
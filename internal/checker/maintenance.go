@@ -0,0 +1,343 @@
+package checker
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/aouxes/uptime-monitor/internal/models"
+	"github.com/aouxes/uptime-monitor/internal/storage"
+)
+
+// maxMaintenanceNameLength — максимальная длина названия окна обслуживания
+const maxMaintenanceNameLength = 200
+
+// maintenanceRefreshInterval — как часто worker'ы перечитывают окна обслуживания из базы
+const maintenanceRefreshInterval = 30 * time.Second
+
+// maintenanceWindow возвращает активное сейчас окно обслуживания сайта или nil
+func (wp *WorkerPool) maintenanceWindow(ctx context.Context, site *models.Site, at time.Time, workerID int) *models.MaintenanceWindow {
+	schedules, err := wp.maintenance.siteSchedules(ctx, wp.storage)
+	if err != nil {
+		log.Printf("Worker %d: Failed to refresh maintenance windows, using cached: %v", workerID, err)
+	}
+
+	for _, schedule := range schedules[site.ID] {
+		if schedule.active(at) {
+			return &schedule.window
+		}
+	}
+	return nil
+}
+
+// maintenanceCache хранит разобранные окна обслуживания по ID сайта.
+// Окна перечитываются из базы не чаще раза в maintenanceRefreshInterval.
+type maintenanceCache struct {
+	mu        sync.Mutex
+	loadedAt  time.Time
+	schedules map[int][]*maintenanceSchedule
+}
+
+// siteSchedules возвращает окна обслуживания по ID сайта, при необходимости перечитывая их.
+// Если перечитать не удалось, возвращает прежние окна вместе с ошибкой.
+func (c *maintenanceCache) siteSchedules(ctx context.Context, storage *storage.Storage) (map[int][]*maintenanceSchedule, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if time.Since(c.loadedAt) < maintenanceRefreshInterval {
+		return c.schedules, nil
+	}
+
+	windows, err := storage.GetActiveMaintenanceWindows(ctx)
+	if err != nil {
+		return c.schedules, err
+	}
+
+	schedules := make(map[int][]*maintenanceSchedule)
+	for _, window := range windows {
+		schedule, err := newMaintenanceSchedule(window)
+		if err != nil {
+			log.Printf("Skipping maintenance window %d: %v", window.ID, err)
+			continue
+		}
+		for _, siteID := range window.SiteIDs {
+			schedules[siteID] = append(schedules[siteID], schedule)
+		}
+	}
+
+	c.schedules, c.loadedAt = schedules, time.Now()
+	return schedules, nil
+}
+
+// maintenanceSchedule — окно обслуживания, разобранное один раз при загрузке
+type maintenanceSchedule struct {
+	window models.MaintenanceWindow
+	loc    *time.Location
+
+	// weekly: дни недели и время начала
+	weekdays     map[time.Weekday]bool
+	hour, minute int
+
+	cron *cronSchedule
+}
+
+// newMaintenanceSchedule разбирает временную зону и расписание окна обслуживания
+func newMaintenanceSchedule(window models.MaintenanceWindow) (*maintenanceSchedule, error) {
+	schedule := &maintenanceSchedule{window: window}
+	if window.Type == models.MaintenanceOnce {
+		return schedule, nil
+	}
+
+	loc, err := time.LoadLocation(window.Timezone)
+	if err != nil {
+		return nil, fmt.Errorf("unknown timezone %q", window.Timezone)
+	}
+	schedule.loc = loc
+
+	switch window.Type {
+	case models.MaintenanceWeekly:
+		start, err := time.Parse("15:04", window.StartTime)
+		if err != nil {
+			return nil, fmt.Errorf("invalid start_time %q, expected HH:MM", window.StartTime)
+		}
+		schedule.hour, schedule.minute = start.Hour(), start.Minute()
+		schedule.weekdays = make(map[time.Weekday]bool, len(window.Weekdays))
+		for _, day := range window.Weekdays {
+			schedule.weekdays[time.Weekday(day)] = true
+		}
+	case models.MaintenanceCron:
+		if schedule.cron, err = parseCron(window.Cron); err != nil {
+			return nil, err
+		}
+	default:
+		return nil, fmt.Errorf("unsupported maintenance type %q", window.Type)
+	}
+
+	return schedule, nil
+}
+
+// active сообщает, идет ли окно обслуживания в момент at.
+// Повторяющееся окно активно, если его последнее начало было меньше DurationMinutes назад.
+func (s *maintenanceSchedule) active(at time.Time) bool {
+	window := s.window
+	if window.Type == models.MaintenanceOnce {
+		return window.StartsAt != nil && window.EndsAt != nil &&
+			!at.Before(*window.StartsAt) && at.Before(*window.EndsAt)
+	}
+
+	since := at.Add(-time.Duration(window.DurationMinutes) * time.Minute)
+	if s.cron != nil {
+		_, ok := s.cron.lastStart(at, since, s.loc)
+		return ok
+	}
+
+	start, ok := s.lastWeeklyStart(at)
+	return ok && start.After(since)
+}
+
+// lastWeeklyStart возвращает последнее начало еженедельного окна не позже at
+func (s *maintenanceSchedule) lastWeeklyStart(at time.Time) (time.Time, bool) {
+	local := at.In(s.loc)
+	for days := 0; days <= 7; days++ {
+		day := local.AddDate(0, 0, -days)
+		start := time.Date(day.Year(), day.Month(), day.Day(), s.hour, s.minute, 0, 0, s.loc)
+		if !start.After(at) && s.weekdays[start.Weekday()] {
+			return start, true
+		}
+	}
+	return time.Time{}, false
+}
+
+// ValidateMaintenanceWindow проверяет окно обслуживания и приводит его поля к каноническому виду
+func ValidateMaintenanceWindow(window *models.MaintenanceWindow) error {
+	window.Name = strings.TrimSpace(window.Name)
+	if len([]rune(window.Name)) > maxMaintenanceNameLength {
+		return fmt.Errorf("name is too long, maximum %d characters", maxMaintenanceNameLength)
+	}
+
+	if window.Timezone == "" {
+		window.Timezone = "UTC"
+	}
+	if _, err := time.LoadLocation(window.Timezone); err != nil {
+		return fmt.Errorf("unknown timezone %q", window.Timezone)
+	}
+
+	if len(window.SiteIDs) == 0 {
+		return fmt.Errorf("site_ids must contain at least one site")
+	}
+
+	switch window.Type {
+	case models.MaintenanceOnce:
+		if window.StartsAt == nil || window.EndsAt == nil {
+			return fmt.Errorf("starts_at and ends_at are required for one-off window")
+		}
+		if !window.EndsAt.After(*window.StartsAt) {
+			return fmt.Errorf("ends_at must be after starts_at")
+		}
+		window.Weekdays, window.StartTime, window.Cron, window.DurationMinutes = nil, "", "", 0
+		return nil
+	case models.MaintenanceWeekly:
+		if len(window.Weekdays) == 0 {
+			return fmt.Errorf("weekdays are required for weekly window")
+		}
+		days := map[int]bool{}
+		for _, day := range window.Weekdays {
+			if day < 0 || day > 6 {
+				return fmt.Errorf("invalid weekday %d, expected 0 (Sunday) - 6 (Saturday)", day)
+			}
+			days[day] = true
+		}
+		window.Weekdays = make([]int, 0, len(days))
+		for day := range days {
+			window.Weekdays = append(window.Weekdays, day)
+		}
+		sort.Ints(window.Weekdays)
+		window.Cron = ""
+	case models.MaintenanceCron:
+		window.Cron = strings.Join(strings.Fields(window.Cron), " ")
+		window.Weekdays, window.StartTime = nil, ""
+	default:
+		return fmt.Errorf("unsupported type %q, expected once, weekly or cron", window.Type)
+	}
+
+	if _, err := newMaintenanceSchedule(*window); err != nil {
+		return err
+	}
+
+	if window.DurationMinutes < 1 || window.DurationMinutes > models.MaxMaintenanceDurationMinutes {
+		return fmt.Errorf("duration_minutes must be between 1 and %d", models.MaxMaintenanceDurationMinutes)
+	}
+
+	window.StartsAt, window.EndsAt = nil, nil
+	return nil
+}
+
+// cronSchedule — разобранное cron выражение из пяти полей
+type cronSchedule struct {
+	minute, hour, day, month, weekday map[int]bool
+	anyDay, anyWeekday                bool
+}
+
+// parseCron разбирает выражение "минута час день месяц день_недели".
+// Поддерживаются *, списки, диапазоны и шаг (*/15, 1-5, 0,30). Воскресенье — 0 или 7.
+func parseCron(expr string) (*cronSchedule, error) {
+	fields := strings.Fields(expr)
+	if len(fields) != 5 {
+		return nil, fmt.Errorf("invalid cron %q, expected 5 fields", expr)
+	}
+
+	bounds := [5][2]int{{0, 59}, {0, 23}, {1, 31}, {1, 12}, {0, 7}}
+	var sets [5]map[int]bool
+	for i, field := range fields {
+		set, err := parseCronField(field, bounds[i][0], bounds[i][1])
+		if err != nil {
+			return nil, fmt.Errorf("invalid cron %q: %w", expr, err)
+		}
+		sets[i] = set
+	}
+
+	if sets[4][7] {
+		sets[4][0] = true
+	}
+
+	return &cronSchedule{
+		minute:     sets[0],
+		hour:       sets[1],
+		day:        sets[2],
+		month:      sets[3],
+		weekday:    sets[4],
+		anyDay:     fields[2] == "*",
+		anyWeekday: fields[4] == "*",
+	}, nil
+}
+
+func parseCronField(field string, low, high int) (map[int]bool, error) {
+	set := map[int]bool{}
+	for _, part := range strings.Split(field, ",") {
+		step := 1
+		if base, stepText, ok := strings.Cut(part, "/"); ok {
+			n, err := strconv.Atoi(stepText)
+			if err != nil || n < 1 {
+				return nil, fmt.Errorf("invalid step in %q", part)
+			}
+			part, step = base, n
+		}
+
+		from, to := low, high
+		if part != "*" {
+			first, last, isRange := strings.Cut(part, "-")
+			var err error
+			if from, err = strconv.Atoi(first); err != nil {
+				return nil, fmt.Errorf("invalid value %q", part)
+			}
+			to = from
+			if step > 1 && !isRange {
+				to = high
+			}
+			if isRange {
+				if to, err = strconv.Atoi(last); err != nil {
+					return nil, fmt.Errorf("invalid value %q", part)
+				}
+			}
+			if from < low || to > high || from > to {
+				return nil, fmt.Errorf("value %q out of range %d-%d", part, low, high)
+			}
+		}
+
+		for value := from; value <= to; value += step {
+			set[value] = true
+		}
+	}
+	return set, nil
+}
+
+// matches сообщает, совпадает ли минута t с расписанием
+func (c *cronSchedule) matches(t time.Time) bool {
+	return c.minute[t.Minute()] && c.hour[t.Hour()] && c.month[int(t.Month())] && c.dayMatches(t)
+}
+
+// dayMatches сообщает, подходит ли расписанию день t. Как в cron, если ограничены
+// и день месяца, и день недели, достаточно совпадения любого из них.
+func (c *cronSchedule) dayMatches(t time.Time) bool {
+	day, weekday := c.day[t.Day()], c.weekday[int(t.Weekday())]
+	switch {
+	case c.anyDay && c.anyWeekday:
+		return true
+	case c.anyDay:
+		return weekday
+	case c.anyWeekday:
+		return day
+	}
+	return day || weekday
+}
+
+// lastStart возвращает последнюю минуту расписания в интервале (since, at] во временной зоне loc.
+// Неподходящие дни и часы пропускаются целиком, поэтому перебираются только минуты подходящих часов.
+func (c *cronSchedule) lastStart(at, since time.Time, loc *time.Location) (time.Time, bool) {
+	t := at.In(loc).Truncate(time.Minute)
+	for t.After(since) {
+		var prev time.Time
+		switch {
+		case !c.month[int(t.Month())] || !c.dayMatches(t):
+			prev = time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, loc).Add(-time.Minute)
+		case !c.hour[t.Hour()]:
+			prev = time.Date(t.Year(), t.Month(), t.Day(), t.Hour(), 0, 0, 0, loc).Add(-time.Minute)
+		case !c.minute[t.Minute()]:
+			prev = t.Add(-time.Minute)
+		default:
+			return t, true
+		}
+
+		// При переводе часов начало дня или часа может оказаться не раньше t
+		if !prev.Before(t) {
+			prev = t.Add(-time.Minute)
+		}
+		t = prev
+	}
+	return time.Time{}, false
+}
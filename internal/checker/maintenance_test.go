@@ -0,0 +1,147 @@
+package checker
+
+import (
+	"testing"
+	"time"
+
+	"github.com/aouxes/uptime-monitor/internal/models"
+)
+
+func TestMaintenanceActive(t *testing.T) {
+	moscow, err := time.LoadLocation("Europe/Moscow")
+	if err != nil {
+		t.Skipf("timezone data unavailable: %v", err)
+	}
+
+	starts := time.Date(2025, 6, 3, 22, 0, 0, 0, time.UTC)
+	ends := starts.Add(time.Hour)
+
+	once := models.MaintenanceWindow{Type: models.MaintenanceOnce, StartsAt: &starts, EndsAt: &ends, Timezone: "UTC"}
+	// Вторник 23:00 по Москве, два часа
+	weekly := models.MaintenanceWindow{Type: models.MaintenanceWeekly, Weekdays: []int{2}, StartTime: "23:00",
+		DurationMinutes: 120, Timezone: "Europe/Moscow"}
+	// Неделя начиная со вторника 23:00 по Москве
+	week := weekly
+	week.DurationMinutes = models.MaxMaintenanceDurationMinutes
+	// Первое число месяца с 03:00 UTC на 30 минут
+	cron := models.MaintenanceWindow{Type: models.MaintenanceCron, Cron: "0 3 1 * *", DurationMinutes: 30, Timezone: "UTC"}
+	// Каждые 15 минут по рабочим дням с 09:00 до 09:59 по Москве, неделю
+	cronWeek := models.MaintenanceWindow{Type: models.MaintenanceCron, Cron: "*/15 9 * * 1-5",
+		DurationMinutes: models.MaxMaintenanceDurationMinutes, Timezone: "Europe/Moscow"}
+	cronShort := cronWeek
+	cronShort.DurationMinutes = 10
+
+	tests := []struct {
+		name   string
+		window models.MaintenanceWindow
+		at     time.Time
+		want   bool
+	}{
+		{"Once before start", once, starts.Add(-time.Second), false},
+		{"Once at start", once, starts, true},
+		{"Once at end", once, ends, false},
+		{"Weekly before start", weekly, time.Date(2025, 6, 3, 22, 59, 0, 0, moscow), false},
+		{"Weekly at start", weekly, time.Date(2025, 6, 3, 23, 0, 0, 0, moscow), true},
+		{"Weekly past midnight", weekly, time.Date(2025, 6, 4, 0, 30, 0, 0, moscow), true},
+		{"Weekly after end", weekly, time.Date(2025, 6, 4, 1, 0, 0, 0, moscow), false},
+		{"Weekly other day", weekly, time.Date(2025, 6, 5, 23, 30, 0, 0, moscow), false},
+		{"Weekly in UTC", weekly, time.Date(2025, 6, 3, 20, 15, 0, 0, time.UTC), true},
+		{"Week-long window before restart", week, time.Date(2025, 6, 10, 22, 59, 0, 0, moscow), true},
+		{"Week-long window after restart", week, time.Date(2025, 6, 10, 23, 0, 0, 0, moscow), true},
+		{"Cron week-long window", cronWeek, time.Date(2025, 6, 8, 18, 0, 0, 0, moscow), true}, // воскресенье, старт в пятницу
+		{"Cron short window inside", cronShort, time.Date(2025, 6, 6, 9, 54, 0, 0, moscow), true},
+		{"Cron short window after end", cronShort, time.Date(2025, 6, 6, 10, 5, 0, 0, moscow), false},
+		{"Cron inside", cron, time.Date(2025, 7, 1, 3, 29, 59, 0, time.UTC), true},
+		{"Cron after end", cron, time.Date(2025, 7, 1, 3, 30, 0, 0, time.UTC), false},
+		{"Cron other day", cron, time.Date(2025, 7, 2, 3, 10, 0, 0, time.UTC), false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			schedule, err := newMaintenanceSchedule(tt.window)
+			if err != nil {
+				t.Fatalf("newMaintenanceSchedule() error = %v", err)
+			}
+			if got := schedule.active(tt.at); got != tt.want {
+				t.Errorf("active() at %s = %v, want %v", tt.at, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseCron(t *testing.T) {
+	tests := []struct {
+		expr    string
+		at      time.Time
+		want    bool
+		wantErr bool
+	}{
+		{expr: "*/15 * * * *", at: time.Date(2025, 6, 3, 10, 45, 0, 0, time.UTC), want: true},
+		{expr: "*/15 * * * *", at: time.Date(2025, 6, 3, 10, 50, 0, 0, time.UTC), want: false},
+		{expr: "5/20 * * * *", at: time.Date(2025, 6, 3, 10, 45, 0, 0, time.UTC), want: true},
+		{expr: "0 22 * * 1-5", at: time.Date(2025, 6, 6, 22, 0, 0, 0, time.UTC), want: true},  // пятница
+		{expr: "0 22 * * 1-5", at: time.Date(2025, 6, 7, 22, 0, 0, 0, time.UTC), want: false}, // суббота
+		{expr: "0 0 * * 7", at: time.Date(2025, 6, 8, 0, 0, 0, 0, time.UTC), want: true},      // воскресенье
+		{expr: "0 0 15 * 1", at: time.Date(2025, 6, 9, 0, 0, 0, 0, time.UTC), want: true},     // понедельник, не 15 число
+		{expr: "0 0 15 * 1", at: time.Date(2025, 6, 10, 0, 0, 0, 0, time.UTC), want: false},
+		{expr: "0 0,12 1 1,7 *", at: time.Date(2025, 7, 1, 12, 0, 0, 0, time.UTC), want: true},
+		{expr: "0 22 * *", wantErr: true},
+		{expr: "60 * * * *", wantErr: true},
+		{expr: "0 5-1 * * *", wantErr: true},
+		{expr: "*/0 * * * *", wantErr: true},
+		{expr: "@daily", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		schedule, err := parseCron(tt.expr)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("parseCron(%q) error = %v, wantErr %v", tt.expr, err, tt.wantErr)
+			continue
+		}
+		if err != nil {
+			continue
+		}
+		if got := schedule.matches(tt.at); got != tt.want {
+			t.Errorf("parseCron(%q).matches(%s) = %v, want %v", tt.expr, tt.at, got, tt.want)
+		}
+	}
+}
+
+func TestValidateMaintenanceWindow(t *testing.T) {
+	starts := time.Date(2025, 6, 3, 22, 0, 0, 0, time.UTC)
+	ends := starts.Add(time.Hour)
+
+	tests := []struct {
+		name    string
+		window  models.MaintenanceWindow
+		noSites bool
+		wantErr bool
+	}{
+		{name: "Once", window: models.MaintenanceWindow{Type: models.MaintenanceOnce, StartsAt: &starts, EndsAt: &ends}},
+		{name: "Once without end", window: models.MaintenanceWindow{Type: models.MaintenanceOnce, StartsAt: &starts}, wantErr: true},
+		{name: "Once reversed", window: models.MaintenanceWindow{Type: models.MaintenanceOnce, StartsAt: &ends, EndsAt: &starts}, wantErr: true},
+		{name: "Weekly", window: models.MaintenanceWindow{Type: models.MaintenanceWeekly, Weekdays: []int{2, 4}, StartTime: "22:00", DurationMinutes: 60}},
+		{name: "Weekly bad day", window: models.MaintenanceWindow{Type: models.MaintenanceWeekly, Weekdays: []int{7}, StartTime: "22:00", DurationMinutes: 60}, wantErr: true},
+		{name: "Weekly bad time", window: models.MaintenanceWindow{Type: models.MaintenanceWeekly, Weekdays: []int{2}, StartTime: "25:00", DurationMinutes: 60}, wantErr: true},
+		{name: "Weekly no duration", window: models.MaintenanceWindow{Type: models.MaintenanceWeekly, Weekdays: []int{2}, StartTime: "22:00"}, wantErr: true},
+		{name: "Cron", window: models.MaintenanceWindow{Type: models.MaintenanceCron, Cron: "0 22 * * 2", DurationMinutes: 60, Timezone: "Europe/Berlin"}},
+		{name: "Cron invalid", window: models.MaintenanceWindow{Type: models.MaintenanceCron, Cron: "0 22 * *", DurationMinutes: 60}, wantErr: true},
+		{name: "Unknown timezone", window: models.MaintenanceWindow{Type: models.MaintenanceCron, Cron: "0 22 * * 2", DurationMinutes: 60, Timezone: "Mars/Olympus"}, wantErr: true},
+		{name: "Unknown type", window: models.MaintenanceWindow{Type: "daily"}, wantErr: true},
+		{name: "No sites", window: models.MaintenanceWindow{Type: models.MaintenanceOnce, StartsAt: &starts, EndsAt: &ends}, noSites: true, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			window := tt.window
+			if !tt.noSites {
+				window.SiteIDs = []int{1}
+			}
+
+			err := ValidateMaintenanceWindow(&window)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ValidateMaintenanceWindow() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,107 @@
+package checker
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/aouxes/uptime-monitor/internal/models"
+)
+
+// pushSweepInterval — как часто проверяются дедлайны push мониторов
+const pushSweepInterval = 15 * time.Second
+
+// RecordPush сохраняет пинг push монитора. failed — задача сообщила о сбое,
+// message — ее описание сбоя.
+func (c *Checker) RecordPush(ctx context.Context, site *models.Site, failed bool, message string) *models.CheckResult {
+	c.pushMu.Lock()
+	defer c.pushMu.Unlock()
+
+	now := time.Now()
+	result := &models.CheckResult{
+		CheckedAt: now,
+		Status:    "UP",
+	}
+	if failed {
+		result.Status = "DOWN"
+		result.Error = message
+		if result.Error == "" {
+			result.Error = "job reported failure"
+		}
+	}
+
+	if err := c.storage.UpdateSitePushedAt(ctx, site.ID, now); err != nil {
+		log.Printf("Failed to save push time for site %s: %v", site.URL, err)
+	}
+	site.LastPushAt = &now
+
+	log.Printf("Push monitor %s received ping: %s", site.URL, result.Status)
+	return c.workerPool.processResult(ctx, site, result, 0)
+}
+
+// runPushSweeper периодически переводит в DOWN push мониторы без своевременного пинга
+func (c *Checker) runPushSweeper(ctx context.Context) {
+	ticker := time.NewTicker(pushSweepInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := c.sweepPushMonitors(ctx); err != nil {
+				log.Printf("Push monitors sweep failed: %v", err)
+			}
+		}
+	}
+}
+
+func (c *Checker) sweepPushMonitors(ctx context.Context) error {
+	c.pushMu.Lock()
+	defer c.pushMu.Unlock()
+
+	sites, err := c.storage.GetPushSites(ctx)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	for _, site := range overduePushSites(sites, now) {
+		window := site.CheckInterval() + time.Duration(site.PushGraceSeconds)*time.Second
+		result := &models.CheckResult{
+			CheckedAt: now,
+			Status:    "DOWN",
+			Error:     fmt.Sprintf("no ping received within %s", window),
+		}
+
+		// Пропуск дедлайна уже подтвержден запасом времени, поэтому порог сбоев не применяется
+		site.ConsecutiveFailures = max(site.FailThreshold-1, 0)
+
+		log.Printf("❌ Push monitor %s is DOWN (%s)", site.URL, result.Error)
+		c.workerPool.processResult(ctx, &site, result, 0)
+	}
+
+	return nil
+}
+
+// overduePushSites возвращает push мониторы, пропустившие дедлайн пинга.
+// Уже упавшие мониторы не возвращаются, чтобы не дублировать результаты.
+// Мониторы в UNREACHABLE и на обслуживании перепроверяются не чаще раза в интервал:
+// так они переходят в DOWN, когда родитель восстановился или окно закончилось.
+func overduePushSites(sites []models.Site, now time.Time) []models.Site {
+	var overdue []models.Site
+	for _, site := range sites {
+		if site.LastStatus == "DOWN" {
+			continue
+		}
+		held := unavailableStatus(site.LastStatus) || site.LastStatus == "MAINTENANCE"
+		if held && now.Sub(site.LastChecked) < site.CheckInterval() {
+			continue
+		}
+		if now.After(site.PushDeadline()) {
+			overdue = append(overdue, site)
+		}
+	}
+	return overdue
+}
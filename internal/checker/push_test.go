@@ -0,0 +1,74 @@
+package checker
+
+import (
+	"testing"
+	"time"
+
+	"github.com/aouxes/uptime-monitor/internal/models"
+)
+
+func TestOverduePushSites(t *testing.T) {
+	now := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+	at := func(ago time.Duration) *time.Time {
+		t := now.Add(-ago)
+		return &t
+	}
+
+	newPushSite := func(id int, status string, lastPush *time.Time) models.Site {
+		site := models.NewSite("nightly-backup", 1)
+		site.ID = id
+		site.MonitorType = models.MonitorPush
+		site.IntervalSeconds = 3600
+		site.PushGraceSeconds = 300
+		site.LastStatus = status
+		site.LastPushAt = lastPush
+		site.CreatedAt = now.Add(-48 * time.Hour)
+		return *site
+	}
+
+	fresh := newPushSite(1, "UP", at(30*time.Minute))
+	inGrace := newPushSite(2, "UP", at(62*time.Minute))
+	late := newPushSite(3, "UP", at(66*time.Minute))
+	alreadyDown := newPushSite(4, "DOWN", at(3*time.Hour))
+	neverPinged := newPushSite(5, "UNKNOWN", nil)
+	justCreated := newPushSite(6, "UNKNOWN", nil)
+	justCreated.CreatedAt = now.Add(-10 * time.Minute)
+	maintenanceChecked := newPushSite(7, "MAINTENANCE", at(3*time.Hour))
+	maintenanceChecked.LastChecked = now.Add(-20 * time.Minute)
+	maintenanceStale := newPushSite(8, "MAINTENANCE", at(3*time.Hour))
+	maintenanceStale.LastChecked = now.Add(-61 * time.Minute)
+	unreachableChecked := newPushSite(9, "UNREACHABLE", at(3*time.Hour))
+	unreachableChecked.LastChecked = now.Add(-5 * time.Minute)
+	unreachableStale := newPushSite(10, "UNREACHABLE", at(3*time.Hour))
+	unreachableStale.LastChecked = now.Add(-61 * time.Minute)
+
+	overdue := overduePushSites([]models.Site{fresh, inGrace, late, alreadyDown, neverPinged, justCreated,
+		maintenanceChecked, maintenanceStale, unreachableChecked, unreachableStale}, now)
+
+	var ids []int
+	for _, site := range overdue {
+		ids = append(ids, site.ID)
+	}
+	if len(ids) != 4 || ids[0] != 3 || ids[1] != 5 || ids[2] != 8 || ids[3] != 10 {
+		t.Errorf("overdue sites = %v, want [3 5 8 10]", ids)
+	}
+}
+
+func TestValidatePushSite(t *testing.T) {
+	site := models.NewSite("nightly-backup", 1)
+	site.MonitorType = models.MonitorPush
+	if err := ValidateSite(site); err != nil {
+		t.Errorf("ValidateSite() error = %v", err)
+	}
+
+	site.PushGraceSeconds = -1
+	if err := ValidateSite(site); err == nil {
+		t.Error("expected error for negative push_grace_seconds")
+	}
+
+	site.PushGraceSeconds = 0
+	site.URL = " "
+	if err := ValidateSite(site); err == nil {
+		t.Error("expected error for empty name")
+	}
+}
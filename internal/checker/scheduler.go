@@ -0,0 +1,258 @@
+package checker
+
+import (
+	"container/heap"
+	"context"
+	"math/rand"
+	"sync"
+	"time"
+
+	"github.com/aouxes/uptime-monitor/internal/models"
+)
+
+// scheduledSite — сайт в очереди планировщика
+type scheduledSite struct {
+	site   models.Site
+	nextAt time.Time
+	index  int // позиция в куче, -1 если сайт сейчас проверяется
+}
+
+// siteQueue — min-heap сайтов по времени следующей проверки
+type siteQueue []*scheduledSite
+
+func (q siteQueue) Len() int           { return len(q) }
+func (q siteQueue) Less(i, j int) bool { return q[i].nextAt.Before(q[j].nextAt) }
+
+func (q siteQueue) Swap(i, j int) {
+	q[i], q[j] = q[j], q[i]
+	q[i].index = i
+	q[j].index = j
+}
+
+func (q *siteQueue) Push(x interface{}) {
+	entry := x.(*scheduledSite)
+	entry.index = len(*q)
+	*q = append(*q, entry)
+}
+
+func (q *siteQueue) Pop() interface{} {
+	old := *q
+	n := len(old)
+	entry := old[n-1]
+	old[n-1] = nil
+	entry.index = -1
+	*q = old[:n-1]
+	return entry
+}
+
+// Scheduler выдает сайты на проверку по мере наступления их времени.
+// Сайт, выданный на проверку, убирается из очереди до вызова Done,
+// поэтому один и тот же сайт никогда не проверяется дважды одновременно.
+type Scheduler struct {
+	mu      sync.Mutex
+	queue   siteQueue
+	entries map[int]*scheduledSite
+	jitter  float64
+	wakeup  chan struct{}
+	now     func() time.Time
+	rand    *rand.Rand
+}
+
+// NewScheduler создает планировщик. jitter — доля интервала (0..1),
+// на которую случайно сдвигается время проверки, чтобы размазать нагрузку.
+func NewScheduler(jitter float64) *Scheduler {
+	return &Scheduler{
+		entries: make(map[int]*scheduledSite),
+		jitter:  jitter,
+		wakeup:  make(chan struct{}, 1),
+		now:     time.Now,
+		rand:    rand.New(rand.NewSource(time.Now().UnixNano())),
+	}
+}
+
+// Sync приводит очередь в соответствие со списком сайтов из базы:
+// добавляет новые, обновляет настройки существующих и удаляет отсутствующие
+func (s *Scheduler) Sync(sites []models.Site) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := s.now()
+	seen := make(map[int]bool, len(sites))
+
+	for _, site := range sites {
+		seen[site.ID] = true
+
+		entry, ok := s.entries[site.ID]
+		if !ok {
+			entry = &scheduledSite{site: site, nextAt: s.firstCheckAt(site, now)}
+			s.entries[site.ID] = entry
+			heap.Push(&s.queue, entry)
+			continue
+		}
+
+		intervalChanged := entry.site.IntervalSeconds != site.IntervalSeconds
+		entry.site = site
+
+		// Сайт в работе — новое время посчитает Done
+		if entry.index < 0 {
+			continue
+		}
+
+		if intervalChanged {
+			entry.nextAt = s.firstCheckAt(site, now)
+			heap.Fix(&s.queue, entry.index)
+		}
+	}
+
+	for id, entry := range s.entries {
+		if seen[id] {
+			continue
+		}
+		if entry.index >= 0 {
+			heap.Remove(&s.queue, entry.index)
+		}
+		delete(s.entries, id)
+	}
+
+	s.notify()
+}
+
+// Run выдает сайты в канал jobs по мере наступления времени их проверки
+func (s *Scheduler) Run(ctx context.Context, jobs chan<- models.Site) {
+	timer := time.NewTimer(time.Hour)
+	defer timer.Stop()
+
+	for {
+		site, wait, ok := s.popDue()
+		if ok {
+			select {
+			case jobs <- site:
+			case <-ctx.Done():
+				return
+			}
+			continue
+		}
+
+		if !timer.Stop() {
+			select {
+			case <-timer.C:
+			default:
+			}
+		}
+		timer.Reset(wait)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-s.wakeup:
+		case <-timer.C:
+		}
+	}
+}
+
+// Done возвращает проверенный сайт в очередь с учетом его интервала.
+// Сайт с неподтвержденным сбоем перепроверяется через интервал повторной проверки.
+// Удаленные за время проверки сайты обратно не добавляются.
+func (s *Scheduler) Done(site models.Site) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	entry, ok := s.entries[site.ID]
+	if !ok || entry.index >= 0 {
+		return
+	}
+
+	entry.site.LastStatus = site.LastStatus
+	entry.site.LastChecked = site.LastChecked
+	entry.site.ConsecutiveFailures = site.ConsecutiveFailures
+	entry.site.ConsecutiveSuccesses = site.ConsecutiveSuccesses
+
+	if retryPending(entry.site) {
+		entry.nextAt = s.now().Add(retryInterval(entry.site))
+	} else {
+		entry.nextAt = s.now().Add(s.withJitter(entry.site.CheckInterval()))
+	}
+	heap.Push(&s.queue, entry)
+	s.notify()
+}
+
+// Acquire забирает сайт из очереди для внеплановой проверки.
+// Возвращает false, если сайт уже проверяется.
+func (s *Scheduler) Acquire(site models.Site) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	entry, ok := s.entries[site.ID]
+	if !ok {
+		// Сайт еще не попал в очередь — регистрируем его как находящийся в работе
+		s.entries[site.ID] = &scheduledSite{site: site, index: -1}
+		return true
+	}
+
+	if entry.index < 0 {
+		return false
+	}
+
+	heap.Remove(&s.queue, entry.index)
+	return true
+}
+
+// popDue извлекает сайт, время проверки которого наступило.
+// Если таких нет, возвращает время ожидания до ближайшей проверки.
+func (s *Scheduler) popDue() (models.Site, time.Duration, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if len(s.queue) == 0 {
+		return models.Site{}, time.Minute, false
+	}
+
+	wait := s.queue[0].nextAt.Sub(s.now())
+	if wait > 0 {
+		return models.Site{}, wait, false
+	}
+
+	entry := heap.Pop(&s.queue).(*scheduledSite)
+	return entry.site, 0, true
+}
+
+// firstCheckAt считает время проверки по времени последней проверки из базы.
+// Просроченные сайты распределяются случайно в пределах доли интервала.
+func (s *Scheduler) firstCheckAt(site models.Site, now time.Time) time.Time {
+	// Неподтвержденный сбой перепроверяем без размазывания
+	if retryPending(site) {
+		next := site.LastChecked.Add(retryInterval(site))
+		if next.After(now) {
+			return next
+		}
+		return now
+	}
+
+	interval := site.CheckInterval()
+	next := site.LastChecked.Add(interval)
+	if site.LastChecked.IsZero() || !next.After(now) {
+		spread := time.Duration(float64(interval) * s.jitter)
+		if spread > 0 {
+			return now.Add(time.Duration(s.rand.Int63n(int64(spread))))
+		}
+		return now
+	}
+	return next
+}
+
+// withJitter случайно сдвигает интервал на ±jitter его длины
+func (s *Scheduler) withJitter(interval time.Duration) time.Duration {
+	spread := float64(interval) * s.jitter
+	if spread <= 0 {
+		return interval
+	}
+	return interval + time.Duration((s.rand.Float64()*2-1)*spread)
+}
+
+// notify будит цикл Run, если очередь изменилась
+func (s *Scheduler) notify() {
+	select {
+	case s.wakeup <- struct{}{}:
+	default:
+	}
+}
@@ -0,0 +1,130 @@
+package checker
+
+import (
+	"testing"
+	"time"
+
+	"github.com/aouxes/uptime-monitor/internal/models"
+)
+
+func newTestScheduler(now time.Time) (*Scheduler, *time.Time) {
+	s := NewScheduler(0)
+	current := now
+	s.now = func() time.Time { return current }
+	return s, &current
+}
+
+func TestSchedulerOrdersByNextCheck(t *testing.T) {
+	now := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	s, _ := newTestScheduler(now)
+
+	s.Sync([]models.Site{
+		{ID: 1, IntervalSeconds: 60, LastChecked: now.Add(-30 * time.Second)},
+		{ID: 2, IntervalSeconds: 60, LastChecked: now.Add(-2 * time.Minute)},
+		{ID: 3, IntervalSeconds: 300},
+	})
+
+	var due []int
+	for {
+		site, _, ok := s.popDue()
+		if !ok {
+			break
+		}
+		due = append(due, site.ID)
+	}
+
+	if len(due) != 2 {
+		t.Fatalf("Expected 2 due sites, got %v", due)
+	}
+
+	_, wait, ok := s.popDue()
+	if ok || wait != 30*time.Second {
+		t.Errorf("Expected site 1 to be due in 30s, got wait %v", wait)
+	}
+}
+
+func TestSchedulerNeverReturnsSiteInFlight(t *testing.T) {
+	now := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	s, current := newTestScheduler(now)
+
+	site := models.Site{ID: 1, IntervalSeconds: 60}
+	s.Sync([]models.Site{site})
+
+	if _, _, ok := s.popDue(); !ok {
+		t.Fatal("Expected site to be due")
+	}
+
+	// Пока сайт проверяется, повторная синхронизация и время не должны вернуть его в очередь
+	*current = now.Add(10 * time.Minute)
+	s.Sync([]models.Site{site})
+
+	if _, _, ok := s.popDue(); ok {
+		t.Error("Site in flight must not be returned again")
+	}
+
+	if s.Acquire(site) {
+		t.Error("Acquire must fail for a site in flight")
+	}
+
+	s.Done(site)
+
+	if _, wait, ok := s.popDue(); ok || wait != time.Minute {
+		t.Errorf("Expected site to be rescheduled in 1m, got wait %v", wait)
+	}
+
+	if !s.Acquire(site) {
+		t.Error("Acquire must succeed for a queued site")
+	}
+}
+
+func TestSchedulerRemovesDeletedSites(t *testing.T) {
+	now := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	s, _ := newTestScheduler(now)
+
+	s.Sync([]models.Site{{ID: 1, IntervalSeconds: 60}, {ID: 2, IntervalSeconds: 60}})
+	site, _, _ := s.popDue()
+
+	// Удаляем оба сайта, один из которых сейчас проверяется
+	s.Sync(nil)
+	s.Done(site)
+
+	if _, _, ok := s.popDue(); ok {
+		t.Error("Deleted sites must not be scheduled")
+	}
+	if len(s.entries) != 0 {
+		t.Errorf("Expected no entries, got %d", len(s.entries))
+	}
+}
+
+func TestSchedulerFastRecheckAfterUnconfirmedFailure(t *testing.T) {
+	now := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	s, _ := newTestScheduler(now)
+
+	site := models.Site{ID: 1, IntervalSeconds: 300, FailThreshold: 3, RetryIntervalSeconds: 20, LastStatus: "UP"}
+	s.Sync([]models.Site{site})
+	site, _, _ = s.popDue()
+
+	// Первый сбой еще не подтвержден — перепроверяем через retry интервал
+	site.ConsecutiveFailures = 1
+	s.Done(site)
+	if _, wait, _ := s.popDue(); wait != 20*time.Second {
+		t.Errorf("Expected fast recheck in 20s, got %v", wait)
+	}
+
+	// После подтверждения сбоя возвращаемся к обычному интервалу
+	s.Acquire(site)
+	site.ConsecutiveFailures = 3
+	site.LastStatus = "DOWN"
+	s.Done(site)
+	if _, wait, _ := s.popDue(); wait != 5*time.Minute {
+		t.Errorf("Expected regular interval 5m, got %v", wait)
+	}
+
+	// Сбой из-за недоступного родителя тоже не перепроверяется ускоренно
+	s.Acquire(site)
+	site.LastStatus = "UNREACHABLE"
+	s.Done(site)
+	if _, wait, _ := s.popDue(); wait != 5*time.Minute {
+		t.Errorf("Expected regular interval 5m for unreachable site, got %v", wait)
+	}
+}
@@ -0,0 +1,72 @@
+package checker
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+type statusCodeRange struct {
+	from int
+	to   int
+}
+
+// StatusCodeSet — набор допустимых HTTP кодов ответа
+type StatusCodeSet []statusCodeRange
+
+// ParseStatusCodes разбирает список кодов и диапазонов вида "200-299,301,401"
+func ParseStatusCodes(spec string) (StatusCodeSet, error) {
+	var set StatusCodeSet
+
+	for _, part := range strings.Split(spec, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+
+		fromStr, toStr, isRange := strings.Cut(part, "-")
+		if !isRange {
+			toStr = fromStr
+		}
+
+		from, err := parseStatusCode(fromStr)
+		if err != nil {
+			return nil, err
+		}
+
+		to, err := parseStatusCode(toStr)
+		if err != nil {
+			return nil, err
+		}
+
+		if from > to {
+			return nil, fmt.Errorf("invalid status code range %q", part)
+		}
+
+		set = append(set, statusCodeRange{from: from, to: to})
+	}
+
+	if len(set) == 0 {
+		return nil, fmt.Errorf("no status codes specified")
+	}
+
+	return set, nil
+}
+
+func parseStatusCode(s string) (int, error) {
+	code, err := strconv.Atoi(strings.TrimSpace(s))
+	if err != nil || code < 100 || code > 599 {
+		return 0, fmt.Errorf("invalid status code %q", s)
+	}
+	return code, nil
+}
+
+// Contains проверяет, входит ли код в набор
+func (s StatusCodeSet) Contains(code int) bool {
+	for _, r := range s {
+		if code >= r.from && code <= r.to {
+			return true
+		}
+	}
+	return false
+}
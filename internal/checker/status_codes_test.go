@@ -0,0 +1,59 @@
+package checker
+
+import "testing"
+
+func TestParseStatusCodes(t *testing.T) {
+	tests := []struct {
+		name     string
+		spec     string
+		accepted []int
+		rejected []int
+		hasError bool
+	}{
+		{
+			name:     "Default range",
+			spec:     "200-399",
+			accepted: []int{200, 301, 399},
+			rejected: []int{199, 400, 500},
+		},
+		{
+			name:     "List with ranges",
+			spec:     "200-299, 401,418",
+			accepted: []int{204, 401, 418},
+			rejected: []int{302, 403},
+		},
+		{name: "Empty", spec: " , ", hasError: true},
+		{name: "Not a number", spec: "2xx", hasError: true},
+		{name: "Out of range", spec: "200-700", hasError: true},
+		{name: "Reversed range", spec: "299-200", hasError: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			set, err := ParseStatusCodes(tt.spec)
+
+			if tt.hasError {
+				if err == nil {
+					t.Error("Expected parse error, but got none")
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("Expected no error, but got: %v", err)
+			}
+
+			for _, code := range tt.accepted {
+				if !set.Contains(code) {
+					t.Errorf("Expected %d to be accepted", code)
+				}
+			}
+
+			for _, code := range tt.rejected {
+				if set.Contains(code) {
+					t.Errorf("Expected %d to be rejected", code)
+				}
+			}
+		})
+	}
+}
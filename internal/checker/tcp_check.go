@@ -0,0 +1,98 @@
+package checker
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"log"
+	"net"
+	"time"
+
+	"github.com/aouxes/uptime-monitor/internal/models"
+)
+
+// maxBannerBytes — сколько байт ответа читается при ожидании баннера
+const maxBannerBytes = 4096
+
+// CheckTCP проверяет, что на адресе site.URL ("host:port") принимаются TCP соединения.
+// Если задан TCPSend, данные отправляются после подключения; если задан TCPExpect,
+// ответ сервера должен содержать эту строку.
+func CheckTCP(ctx context.Context, site models.Site) (*models.CheckResult, error) {
+	address := site.URL
+
+	start := time.Now()
+	result := &models.CheckResult{
+		CheckedAt: start,
+		Status:    "DOWN",
+	}
+
+	dialer := &net.Dialer{Timeout: 10 * time.Second}
+	conn, err := dialer.DialContext(ctx, "tcp", address)
+	result.ConnectMs = time.Since(start).Milliseconds()
+	if err != nil {
+		result.ResponseTimeMs = result.ConnectMs
+		result.Error = err.Error()
+		log.Printf("❌ TCP %s is DOWN (error: %v)", address, err)
+		return result, err
+	}
+	defer conn.Close()
+
+	deadline := start.Add(10 * time.Second)
+	if ctxDeadline, ok := ctx.Deadline(); ok && ctxDeadline.Before(deadline) {
+		deadline = ctxDeadline
+	}
+	conn.SetDeadline(deadline)
+
+	if site.TCPSend != "" {
+		if _, err := conn.Write([]byte(site.TCPSend)); err != nil {
+			result.ResponseTimeMs = time.Since(start).Milliseconds()
+			result.Error = fmt.Sprintf("failed to send data: %v", err)
+			log.Printf("❌ TCP %s is DOWN (%s)", address, result.Error)
+			return result, err
+		}
+	}
+
+	if site.TCPExpect != "" {
+		received, err := readUntil(conn, []byte(site.TCPExpect))
+		result.TTFBMs = time.Since(start).Milliseconds()
+		result.ResponseTimeMs = result.TTFBMs
+		if err != nil {
+			result.Error = fmt.Sprintf("expected %q, got %q (%v)", site.TCPExpect, truncate(received, 100), err)
+			log.Printf("❌ TCP %s is DOWN (%s)", address, result.Error)
+			return result, nil
+		}
+	} else {
+		result.ResponseTimeMs = time.Since(start).Milliseconds()
+	}
+
+	log.Printf("✅ TCP %s is UP (time: %dms)", address, result.ResponseTimeMs)
+	result.Status = "UP"
+	return result, nil
+}
+
+// readUntil читает из соединения, пока не встретится expected, не закончатся данные
+// или не будет прочитано maxBannerBytes байт
+func readUntil(conn net.Conn, expected []byte) ([]byte, error) {
+	var received []byte
+	buf := make([]byte, 512)
+
+	for len(received) < maxBannerBytes {
+		n, err := conn.Read(buf)
+		received = append(received, buf[:n]...)
+		if bytes.Contains(received, expected) {
+			return received, nil
+		}
+		if err != nil {
+			return received, err
+		}
+	}
+
+	return received, fmt.Errorf("expected data not found in first %d bytes", maxBannerBytes)
+}
+
+func truncate(data []byte, max int) string {
+	if len(data) > max {
+		return string(data[:max]) + "..."
+	}
+	return string(data)
+}
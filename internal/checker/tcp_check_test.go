@@ -0,0 +1,115 @@
+package checker
+
+import (
+	"bufio"
+	"context"
+	"net"
+	"testing"
+
+	"github.com/aouxes/uptime-monitor/internal/models"
+)
+
+// startTCPServer запускает локальный сервер, который отправляет баннер
+// и отвечает PONG на PING
+func startTCPServer(t *testing.T) string {
+	t.Helper()
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listen: %v", err)
+	}
+	t.Cleanup(func() { listener.Close() })
+
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			go func(conn net.Conn) {
+				defer conn.Close()
+				conn.Write([]byte("220 test ready\r\n"))
+				line, err := bufio.NewReader(conn).ReadString('\n')
+				if err != nil {
+					return
+				}
+				if line == "PING\n" {
+					conn.Write([]byte("PONG\n"))
+				}
+			}(conn)
+		}
+	}()
+
+	return listener.Addr().String()
+}
+
+func TestCheckTCP(t *testing.T) {
+	address := startTCPServer(t)
+
+	// Свободный порт: слушаем и сразу закрываем
+	closed, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listen: %v", err)
+	}
+	closedAddress := closed.Addr().String()
+	closed.Close()
+
+	tests := []struct {
+		name    string
+		address string
+		send    string
+		expect  string
+		status  string
+	}{
+		{name: "Connect only", address: address, status: "UP"},
+		{name: "Banner", address: address, expect: "220", status: "UP"},
+		{name: "Send and expect", address: address, send: "PING\n", expect: "PONG", status: "UP"},
+		{name: "Unexpected reply", address: address, send: "HELLO\n", expect: "PONG", status: "DOWN"},
+		{name: "Connection refused", address: closedAddress, status: "DOWN"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			site := models.NewSite(tt.address, 1)
+			site.MonitorType = models.MonitorTCP
+			site.TCPSend = tt.send
+			site.TCPExpect = tt.expect
+
+			result, _ := RunCheck(context.Background(), *site)
+			if result.Status != tt.status {
+				t.Errorf("status = %s, want %s (error: %s)", result.Status, tt.status, result.Error)
+			}
+			if tt.status == "DOWN" && result.Error == "" {
+				t.Error("expected error for DOWN result")
+			}
+		})
+	}
+}
+
+func TestValidateSiteTarget(t *testing.T) {
+	tests := []struct {
+		monitorType string
+		target      string
+		wantErr     bool
+	}{
+		{models.MonitorHTTP, "https://example.com", false},
+		{models.MonitorHTTP, "example.com", true},
+		{models.MonitorHTTP, "ftp://example.com", true},
+		{models.MonitorTCP, "db.example.com:5432", false},
+		{models.MonitorTCP, "[::1]:22", false},
+		{models.MonitorTCP, "db.example.com", true},
+		{models.MonitorTCP, "db.example.com:0", true},
+		{models.MonitorTCP, "db.example.com:70000", true},
+		{"udp", "db.example.com:53", true},
+	}
+
+	for _, tt := range tests {
+		site := models.NewSite(tt.target, 1)
+		site.MonitorType = tt.monitorType
+
+		err := ValidateSite(site)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("ValidateSite(%s %q) error = %v, wantErr %v", tt.monitorType, tt.target, err, tt.wantErr)
+		}
+	}
+}
@@ -0,0 +1,85 @@
+package checker
+
+import (
+	"crypto/tls"
+	"net/http/httptrace"
+	"sync"
+	"time"
+
+	"github.com/aouxes/uptime-monitor/internal/models"
+)
+
+// traceTimings собирает длительности фаз HTTP запроса (DNS, TCP, TLS, первый байт).
+// Колбэки httptrace могут вызываться из разных горутин, поэтому доступ под мьютексом.
+type traceTimings struct {
+	mu           sync.Mutex
+	start        time.Time
+	dnsStart     time.Time
+	connectStart time.Time
+	tlsStart     time.Time
+	dns          time.Duration
+	connect      time.Duration
+	tls          time.Duration
+	ttfb         time.Duration
+}
+
+func newTraceTimings(start time.Time) *traceTimings {
+	return &traceTimings{start: start}
+}
+
+func (t *traceTimings) clientTrace() *httptrace.ClientTrace {
+	return &httptrace.ClientTrace{
+		DNSStart: func(httptrace.DNSStartInfo) {
+			t.mu.Lock()
+			t.dnsStart = time.Now()
+			t.mu.Unlock()
+		},
+		DNSDone: func(httptrace.DNSDoneInfo) {
+			t.mu.Lock()
+			t.dns = time.Since(t.dnsStart)
+			t.mu.Unlock()
+		},
+		ConnectStart: func(_, _ string) {
+			t.mu.Lock()
+			t.connectStart = time.Now()
+			t.mu.Unlock()
+		},
+		ConnectDone: func(_, _ string, err error) {
+			if err != nil {
+				return
+			}
+			t.mu.Lock()
+			t.connect = time.Since(t.connectStart)
+			t.mu.Unlock()
+		},
+		TLSHandshakeStart: func() {
+			t.mu.Lock()
+			t.tlsStart = time.Now()
+			t.mu.Unlock()
+		},
+		TLSHandshakeDone: func(_ tls.ConnectionState, err error) {
+			if err != nil {
+				return
+			}
+			t.mu.Lock()
+			t.tls = time.Since(t.tlsStart)
+			t.mu.Unlock()
+		},
+		GotFirstResponseByte: func() {
+			t.mu.Lock()
+			t.ttfb = time.Since(t.start)
+			t.mu.Unlock()
+		},
+	}
+}
+
+// apply записывает собранные длительности в результат проверки
+func (t *traceTimings) apply(result *models.CheckResult) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	result.DNSMs = t.dns.Milliseconds()
+	result.ConnectMs = t.connect.Milliseconds()
+	result.TLSMs = t.tls.Milliseconds()
+	result.TTFBMs = t.ttfb.Milliseconds()
+}
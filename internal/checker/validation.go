@@ -0,0 +1,133 @@
+package checker
+
+import (
+	"fmt"
+	"net"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/aouxes/uptime-monitor/internal/models"
+)
+
+var allowedHTTPMethods = map[string]bool{
+	"GET":     true,
+	"HEAD":    true,
+	"POST":    true,
+	"PUT":     true,
+	"PATCH":   true,
+	"DELETE":  true,
+	"OPTIONS": true,
+}
+
+// ValidateSite проверяет настройки проверки сайта перед сохранением
+func ValidateSite(site *models.Site) error {
+	if err := validateThresholds(site); err != nil {
+		return err
+	}
+
+	switch site.MonitorType {
+	case models.MonitorHTTP:
+		return validateHTTPSite(site)
+	case models.MonitorTCP:
+		return validateTCPSite(site)
+	case models.MonitorDNS:
+		return validateDNSSite(site)
+	case models.MonitorPush:
+		return validatePushSite(site)
+	default:
+		return fmt.Errorf("unsupported monitor_type %q", site.MonitorType)
+	}
+}
+
+func validateHTTPSite(site *models.Site) error {
+	parsed, err := url.Parse(site.URL)
+	if err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" {
+		return fmt.Errorf("Invalid URL")
+	}
+
+	if !allowedHTTPMethods[site.HTTPMethod] {
+		return fmt.Errorf("unsupported http_method %q", site.HTTPMethod)
+	}
+
+	for name, value := range site.HTTPHeaders {
+		if name == "" || strings.ContainsAny(name, " :\r\n\t") {
+			return fmt.Errorf("invalid header name %q", name)
+		}
+		if strings.ContainsAny(value, "\r\n") {
+			return fmt.Errorf("invalid value for header %q", name)
+		}
+	}
+
+	if _, err := ParseStatusCodes(site.ExpectedStatusCodes); err != nil {
+		return fmt.Errorf("invalid expected_status_codes: %w", err)
+	}
+
+	if site.MaxRedirects < 0 || site.MaxRedirects > 20 {
+		return fmt.Errorf("max_redirects must be between 0 and 20")
+	}
+
+	switch site.KeywordMode {
+	case "":
+	case models.KeywordContains, models.KeywordNotContains:
+		if site.Keyword == "" {
+			return fmt.Errorf("keyword is required for keyword_mode %q", site.KeywordMode)
+		}
+	case models.KeywordRegex:
+		if _, err := regexp.Compile(site.Keyword); err != nil {
+			return fmt.Errorf("invalid keyword regex: %w", err)
+		}
+	default:
+		return fmt.Errorf("unsupported keyword_mode %q", site.KeywordMode)
+	}
+
+	if err := validateJSONAssertions(site.JSONAssertions); err != nil {
+		return fmt.Errorf("invalid json_assertions: %w", err)
+	}
+
+	return nil
+}
+
+func validateTCPSite(site *models.Site) error {
+	host, portStr, err := net.SplitHostPort(site.URL)
+	if err != nil || host == "" {
+		return fmt.Errorf("Invalid address, expected host:port")
+	}
+
+	port, err := strconv.Atoi(portStr)
+	if err != nil || port < 1 || port > 65535 {
+		return fmt.Errorf("Invalid port %q", portStr)
+	}
+
+	return nil
+}
+
+func validatePushSite(site *models.Site) error {
+	if strings.TrimSpace(site.URL) == "" || len(site.URL) > 255 {
+		return fmt.Errorf("Invalid name")
+	}
+
+	if site.PushGraceSeconds < 0 || site.PushGraceSeconds > models.MaxCheckInterval {
+		return fmt.Errorf("push_grace_seconds must be between 0 and %d", models.MaxCheckInterval)
+	}
+
+	return nil
+}
+
+func validateThresholds(site *models.Site) error {
+	if site.FailThreshold < 1 || site.FailThreshold > models.MaxStatusThreshold {
+		return fmt.Errorf("fail_threshold must be between 1 and %d", models.MaxStatusThreshold)
+	}
+
+	if site.RecoverThreshold < 1 || site.RecoverThreshold > models.MaxStatusThreshold {
+		return fmt.Errorf("recover_threshold must be between 1 and %d", models.MaxStatusThreshold)
+	}
+
+	if site.RetryIntervalSeconds != 0 &&
+		(site.RetryIntervalSeconds < models.MinRetryInterval || site.RetryIntervalSeconds > models.MaxRetryInterval) {
+		return fmt.Errorf("retry_interval_seconds must be 0 or between %d and %d", models.MinRetryInterval, models.MaxRetryInterval)
+	}
+
+	return nil
+}
@@ -2,9 +2,12 @@ package checker
 
 import (
 	"context"
+	"fmt"
+	"io"
 	"log"
 	"net/http"
-	"sync"
+	"net/http/httptrace"
+	"strings"
 	"time"
 
 	"github.com/aouxes/uptime-monitor/internal/models"
@@ -13,115 +16,295 @@ import (
 )
 
 type WorkerPool struct {
-	storage      *storage.Storage
-	maxWorkers   int
-	checkTimeout time.Duration
-	notifier     *notifier.Notifier
+	storage        *storage.Storage
+	maxWorkers     int
+	checkTimeout   time.Duration
+	certThresholds []int
+	maintenance    maintenanceCache
 }
 
-func NewWorkerPool(storage *storage.Storage, maxWorkers int, notifier *notifier.Notifier) *WorkerPool {
+func NewWorkerPool(storage *storage.Storage, maxWorkers int, certThresholds []int) *WorkerPool {
 	return &WorkerPool{
-		storage:      storage,
-		maxWorkers:   maxWorkers,
-		checkTimeout: 15 * time.Second,
-		notifier:     notifier,
+		storage:        storage,
+		maxWorkers:     maxWorkers,
+		checkTimeout:   15 * time.Second,
+		certThresholds: certThresholds,
 	}
 }
 
-func (wp *WorkerPool) ProcessSites(ctx context.Context, sites []models.Site) {
-	var wg sync.WaitGroup
-	sitesCh := make(chan models.Site, len(sites))
-
+// Start запускает постоянный набор worker'ов, читающих сайты из jobs.
+// После каждой проверки вызывается done с обновленным сайтом.
+func (wp *WorkerPool) Start(ctx context.Context, jobs <-chan models.Site, done func(models.Site)) {
 	log.Printf("Starting %d workers for parallel site checking...", wp.maxWorkers)
 
-	// Запускаем worker'ов
 	for i := 0; i < wp.maxWorkers; i++ {
-		wg.Add(1)
-		go wp.worker(ctx, &wg, sitesCh, i+1)
-	}
-
-	// Отправляем сайты в канал
-	for _, site := range sites {
-		sitesCh <- site
+		go wp.worker(ctx, jobs, done, i+1)
 	}
-	close(sitesCh)
-
-	wg.Wait()
-	log.Printf("All workers completed processing %d sites", len(sites))
 }
 
-func (wp *WorkerPool) worker(ctx context.Context, wg *sync.WaitGroup, sitesCh <-chan models.Site, workerID int) {
-	defer wg.Done()
-
-	for site := range sitesCh {
+func (wp *WorkerPool) worker(ctx context.Context, jobs <-chan models.Site, done func(models.Site), workerID int) {
+	for {
 		select {
 		case <-ctx.Done():
 			return
-		default:
-			wp.processSite(ctx, site, workerID)
+		case site := <-jobs:
+			wp.processSite(ctx, &site, workerID)
+			done(site)
 		}
 	}
 }
 
-// processSite обрабатывает один сайт
-func (wp *WorkerPool) processSite(ctx context.Context, site models.Site, workerID int) {
+// processSite проверяет один сайт, сохраняет результат и обновляет статус в site
+func (wp *WorkerPool) processSite(ctx context.Context, site *models.Site, workerID int) *models.CheckResult {
 	ctx, cancel := context.WithTimeout(ctx, wp.checkTimeout)
 	defer cancel()
 
 	log.Printf("Worker %d: Checking site %s", workerID, site.URL)
 
-	// Сохраняем старый статус для сравнения
-	oldStatus := site.LastStatus
-
-	// Вызываем статический метод CheckSite
-	status, err := CheckSite(ctx, site.URL)
+	// Выполняем проверку в зависимости от типа монитора
+	result, err := RunCheck(ctx, *site)
 	if err != nil {
 		if ctx.Err() == context.DeadlineExceeded {
 			log.Printf("Worker %d: Timeout checking site %s (%v)", workerID, site.URL, wp.checkTimeout)
 		} else {
 			log.Printf("Worker %d: Failed to check site %s: %v", workerID, site.URL, err)
 		}
-		status = "DOWN"
 	} else {
-		log.Printf("Worker %d: Site %s is %s", workerID, site.URL, status)
+		log.Printf("Worker %d: Site %s is %s", workerID, site.URL, result.Status)
 	}
 
-	// Обновляем статус в базе данных
-	if err := wp.storage.UpdateSiteStatus(ctx, site.ID, status); err != nil {
-		log.Printf("Worker %d: Failed to update site %s status: %v", workerID, site.URL, err)
-	} else {
-		// Отправляем уведомление если статус изменился
-		if oldStatus != status {
-			if err := wp.notifier.NotifySiteStatusChange(ctx, site.ID, oldStatus, status); err != nil {
-				log.Printf("Worker %d: Failed to send notification for site %s: %v", workerID, site.URL, err)
-			}
+	return wp.processResult(ctx, site, result, workerID)
+}
+
+// processResult сохраняет результат проверки, обновляет статус в site
+// и ставит в очередь уведомление владельца при смене статуса
+func (wp *WorkerPool) processResult(ctx context.Context, site *models.Site, result *models.CheckResult, workerID int) *models.CheckResult {
+	// Сохраняем старый статус для сравнения
+	oldStatus := site.LastStatus
+
+	result.SiteID = site.ID
+	status, failures, successes := confirmStatus(*site, result.Status)
+
+	// Во время окна обслуживания проверка сохраняется, но сайт получает статус MAINTENANCE
+	if window := wp.maintenanceWindow(ctx, site, result.CheckedAt, workerID); window != nil {
+		log.Printf("Worker %d: Site %s is in maintenance window %d, check is %s", workerID, site.URL, window.ID, result.Status)
+		result.Status = "MAINTENANCE"
+		status, failures, successes = "MAINTENANCE", 0, 0
+	} else if result.Status != "UP" && wp.parentUnavailable(ctx, site, workerID) {
+		// Сбой вызван недоступностью родителя: сайт не считается упавшим сам по себе
+		log.Printf("Worker %d: Site %s check is %s while parent site %d is unavailable", workerID, site.URL, result.Status, site.ParentSiteID)
+		result.Status = "UNREACHABLE"
+		if status == "DOWN" {
+			status = "UNREACHABLE"
 		}
 	}
+
+	// Сохраняем результат в историю проверок вместе с подтвержденным статусом сайта
+	result.ConfirmedStatus = status
+	if err := wp.storage.CreateCheckResult(ctx, result); err != nil {
+		log.Printf("Worker %d: Failed to save check result for site %s: %v", workerID, site.URL, err)
+	}
+
+	if result.Certificate != nil {
+		wp.processCertificate(ctx, site, result.Certificate, workerID)
+	}
+
+	// Инцидент обновляется до статуса, чтобы уведомление ссылалось на открытый инцидент
+	incidentID := wp.trackIncident(ctx, site, oldStatus, status, result, workerID)
+
+	// Обновляем статус в базе данных, уведомление о смене статуса встает в очередь вместе с ним
+	notification := notifier.StatusChangeNotification(oldStatus, status, result.Error, incidentID)
+	if err := wp.storage.UpdateSiteStatus(ctx, site.ID, status, failures, successes, notification); err != nil {
+		log.Printf("Worker %d: Failed to update site %s status: %v", workerID, site.URL, err)
+		return result
+	}
+
+	if status != result.Status {
+		log.Printf("Worker %d: Site %s check is %s, keeping %s (%d failures, %d successes in a row)",
+			workerID, site.URL, result.Status, status, failures, successes)
+	}
+	if oldStatus != status && notification == nil {
+		log.Printf("Worker %d: Site %s status changed %s -> %s, notification skipped", workerID, site.URL, oldStatus, status)
+	}
+
+	site.LastStatus = status
+	site.LastChecked = result.CheckedAt
+	site.ConsecutiveFailures = failures
+	site.ConsecutiveSuccesses = successes
+
+	return result
+}
+
+// processCertificate сохраняет сертификат сайта и предупреждает о скором истечении
+func (wp *WorkerPool) processCertificate(ctx context.Context, site *models.Site, cert *models.SiteCertificate, workerID int) {
+	cert.SiteID = site.ID
+	site.CertExpiresAt = &cert.NotAfter
+
+	lastWarned, err := wp.storage.UpsertSiteCertificate(ctx, cert)
+	if err != nil {
+		log.Printf("Worker %d: Failed to save certificate for site %s: %v", workerID, site.URL, err)
+		return
+	}
+
+	// Об истекшем сертификате сообщает уведомление о DOWN
+	daysLeft := daysUntil(cert.NotAfter, time.Now())
+	if daysLeft < 0 {
+		return
+	}
+
+	threshold, ok := certWarningThreshold(daysLeft, wp.certThresholds, lastWarned)
+	if !ok {
+		return
+	}
+
+	log.Printf("Worker %d: Certificate for site %s expires in %d days", workerID, site.URL, daysLeft)
+	if err := wp.storage.SetCertificateWarned(ctx, site.ID, threshold, notifier.CertExpiryNotification(cert, daysLeft)); err != nil {
+		log.Printf("Worker %d: Failed to save certificate warning for site %s: %v", workerID, site.URL, err)
+	}
 }
 
-func CheckSite(ctx context.Context, url string) (string, error) {
-	client := &http.Client{Timeout: 10 * time.Second}
+// RunCheck выполняет проверку сайта в зависимости от типа монитора
+func RunCheck(ctx context.Context, site models.Site) (*models.CheckResult, error) {
+	switch site.MonitorType {
+	case models.MonitorTCP:
+		return CheckTCP(ctx, site)
+	case models.MonitorDNS:
+		return CheckDNS(ctx, site)
+	default:
+		return CheckSite(ctx, site)
+	}
+}
+
+// CheckSite выполняет HTTP проверку сайта с учетом его настроек (метод, заголовки, тело,
+// ожидаемые коды ответа, редиректы) и возвращает результат.
+// Результат возвращается всегда, даже вместе с ошибкой (тогда статус DOWN).
+func CheckSite(ctx context.Context, site models.Site) (*models.CheckResult, error) {
+	url := site.URL
+
+	// Отдельный транспорт без keep-alive, чтобы каждая проверка измеряла DNS, TCP и TLS заново
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.DisableKeepAlives = true
+	defer transport.CloseIdleConnections()
+
+	client := &http.Client{
+		Timeout:       10 * time.Second,
+		Transport:     transport,
+		CheckRedirect: redirectPolicy(site),
+	}
 
 	start := time.Now()
-	req, err := http.NewRequestWithContext(ctx, "HEAD", url, nil)
+	result := &models.CheckResult{
+		CheckedAt: start,
+		Status:    "DOWN",
+	}
+
+	expected, err := ParseStatusCodes(site.ExpectedStatusCodes)
+	if err != nil {
+		expected, _ = ParseStatusCodes(models.DefaultExpectedStatusCodes)
+	}
+
+	timings := newTraceTimings(start)
+	ctx = httptrace.WithClientTrace(ctx, timings.clientTrace())
+
+	method := site.HTTPMethod
+	if method == "" {
+		method = models.DefaultHTTPMethod
+	}
+
+	// HEAD не возвращает тело, поэтому для проверки содержимого используем GET
+	if method == http.MethodHead && site.HasBodyAssertions() {
+		method = http.MethodGet
+	}
+
+	var body io.Reader
+	if site.HTTPBody != "" {
+		body = strings.NewReader(site.HTTPBody)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, url, body)
 	if err != nil {
-		return "DOWN", err
+		result.Error = err.Error()
+		return result, err
+	}
+
+	// Сохраняем цепочку сертификатов HTTPS сайта и проверяем ее сами
+	certs := &certificateCapture{}
+	transport.TLSClientConfig = certs.tlsConfig(req.URL.Hostname())
+
+	for name, value := range site.HTTPHeaders {
+		if strings.EqualFold(name, "Host") {
+			req.Host = value
+			continue
+		}
+		req.Header.Set(name, value)
 	}
 
 	resp, err := client.Do(req)
 	checkTime := time.Since(start)
+	result.ResponseTimeMs = checkTime.Milliseconds()
+	timings.apply(result)
+	result.Certificate = certs.result()
 
 	if err != nil {
 		log.Printf("❌ Site %s is DOWN (error: %v, time: %v)", url, err, checkTime)
-		return "DOWN", err
+		result.Error = err.Error()
+		if reason, ok := describeCertificateError(err); ok {
+			result.Error = reason
+		}
+		return result, err
 	}
 	defer resp.Body.Close()
 
-	if resp.StatusCode >= 200 && resp.StatusCode < 400 {
-		log.Printf("✅ Site %s is UP (status: %d, time: %v)", url, resp.StatusCode, checkTime)
-		return "UP", nil
+	result.StatusCode = resp.StatusCode
+
+	if !expected.Contains(resp.StatusCode) {
+		log.Printf("❌ Site %s is DOWN (status: %d, time: %v)", url, resp.StatusCode, checkTime)
+		result.Error = fmt.Sprintf("unexpected status code %d", resp.StatusCode)
+		return result, nil
 	}
 
-	log.Printf("❌ Site %s is DOWN (status: %d, time: %v)", url, resp.StatusCode, checkTime)
-	return "DOWN", nil
+	if site.HasBodyAssertions() {
+		body, err := io.ReadAll(io.LimitReader(resp.Body, maxBodyBytes))
+		if err != nil {
+			log.Printf("❌ Site %s is DOWN (failed to read body: %v)", url, err)
+			result.Error = fmt.Sprintf("failed to read body: %v", err)
+			return result, err
+		}
+
+		reason, err := checkKeyword(site, body)
+		if err != nil {
+			result.Error = err.Error()
+			return result, err
+		}
+
+		if reason != "" {
+			log.Printf("❌ Site %s is DOWN (%s)", url, reason)
+			result.Error = reason
+			return result, nil
+		}
+
+		if failures := checkJSONAssertions(site.JSONAssertions, body); len(failures) > 0 {
+			log.Printf("❌ Site %s is DOWN (%d JSON assertions failed)", url, len(failures))
+			result.AssertionFailures = failures
+			result.Error = "json assertion failed: " + strings.Join(failures, "; ")
+			return result, nil
+		}
+	}
+
+	log.Printf("✅ Site %s is UP (status: %d, time: %v)", url, resp.StatusCode, checkTime)
+	result.Status = "UP"
+	return result, nil
+}
+
+// redirectPolicy возвращает политику редиректов по настройкам сайта
+func redirectPolicy(site models.Site) func(req *http.Request, via []*http.Request) error {
+	return func(req *http.Request, via []*http.Request) error {
+		if !site.FollowRedirects {
+			return http.ErrUseLastResponse
+		}
+		if len(via) > site.MaxRedirects {
+			return fmt.Errorf("stopped after %d redirects", site.MaxRedirects)
+		}
+		return nil
+	}
 }
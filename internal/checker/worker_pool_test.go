@@ -0,0 +1,118 @@
+package checker
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/aouxes/uptime-monitor/internal/models"
+)
+
+func TestCheckSiteHTTPOptions(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/teapot":
+			body, _ := io.ReadAll(r.Body)
+			if r.Method != http.MethodPost || r.Header.Get("Authorization") != "Bearer secret" || string(body) != `{"ping":true}` {
+				w.WriteHeader(http.StatusBadRequest)
+				return
+			}
+			w.WriteHeader(http.StatusTeapot)
+		case "/redirect":
+			http.Redirect(w, r, "/teapot", http.StatusFound)
+		default:
+			w.WriteHeader(http.StatusOK)
+		}
+	}))
+	defer server.Close()
+
+	tests := []struct {
+		name       string
+		configure  func(site *models.Site)
+		status     string
+		statusCode int
+	}{
+		{
+			name:       "Defaults",
+			configure:  func(site *models.Site) {},
+			status:     "UP",
+			statusCode: http.StatusOK,
+		},
+		{
+			name: "POST with headers and accepted 418",
+			configure: func(site *models.Site) {
+				site.URL = server.URL + "/teapot"
+				site.HTTPMethod = http.MethodPost
+				site.HTTPHeaders = map[string]string{"Authorization": "Bearer secret"}
+				site.HTTPBody = `{"ping":true}`
+				site.ExpectedStatusCodes = "200-299,418"
+			},
+			status:     "UP",
+			statusCode: http.StatusTeapot,
+		},
+		{
+			name: "Unexpected status code",
+			configure: func(site *models.Site) {
+				site.URL = server.URL + "/teapot"
+			},
+			status:     "DOWN",
+			statusCode: http.StatusBadRequest,
+		},
+		{
+			name: "Redirects not followed",
+			configure: func(site *models.Site) {
+				site.URL = server.URL + "/redirect"
+				site.FollowRedirects = false
+				site.ExpectedStatusCodes = "200"
+			},
+			status:     "DOWN",
+			statusCode: http.StatusFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			site := models.NewSite(server.URL, 1)
+			tt.configure(site)
+
+			result, err := CheckSite(context.Background(), *site)
+			if err != nil {
+				t.Fatalf("CheckSite failed: %v", err)
+			}
+
+			if result.Status != tt.status || result.StatusCode != tt.statusCode {
+				t.Errorf("Expected %s/%d, got %s/%d (%s)", tt.status, tt.statusCode, result.Status, result.StatusCode, result.Error)
+			}
+		})
+	}
+}
+
+func TestCheckSiteKeyword(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodHead {
+			w.WriteHeader(http.StatusMethodNotAllowed)
+			return
+		}
+		w.Write([]byte("Database connection failed"))
+	}))
+	defer server.Close()
+
+	site := models.NewSite(server.URL, 1)
+	site.Keyword = "Database connection failed"
+	site.KeywordMode = models.KeywordNotContains
+
+	result, err := CheckSite(context.Background(), *site)
+	if err != nil {
+		t.Fatalf("CheckSite failed: %v", err)
+	}
+
+	if result.Status != "DOWN" || result.StatusCode != http.StatusOK {
+		t.Errorf("Expected DOWN with 200, got %s/%d", result.Status, result.StatusCode)
+	}
+
+	if result.Error == "" {
+		t.Error("Expected failure reason to be recorded")
+	}
+}
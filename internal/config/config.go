@@ -1,9 +1,11 @@
 package config
 
 import (
+	"fmt"
 	"log"
 	"os"
 	"strconv"
+	"strings"
 
 	"github.com/joho/godotenv"
 )
@@ -17,6 +19,37 @@ type Config struct {
 	ServerPort    string
 	JWTSecret     string
 	TelegramToken string
+
+	// Пороги (в днях) предупреждений об истечении TLS сертификата
+	CertExpiryThresholds []int
+
+	SMTP SMTPConfig
+
+	// Базовые адреса API систем оповещения дежурных (меняются для тестов и EU региона Opsgenie)
+	PagerDutyAPIURL string
+	OpsgenieAPIURL  string
+}
+
+// Режимы шифрования SMTP соединения
+const (
+	SMTPStartTLS = "starttls" // обычное соединение с переходом на TLS командой STARTTLS
+	SMTPTLS      = "tls"      // TLS с момента подключения (обычно порт 465)
+	SMTPNone     = "none"     // без шифрования, только для локальных серверов
+)
+
+// SMTPConfig — сервер для отправки email уведомлений. Без Host email уведомления отключены.
+type SMTPConfig struct {
+	Host     string
+	Port     int
+	Username string // без имени пользователя авторизация не выполняется
+	Password string
+	From     string
+	TLSMode  string
+}
+
+// Enabled сообщает, что SMTP сервер настроен
+func (c SMTPConfig) Enabled() bool {
+	return c.Host != ""
 }
 
 func Load() *Config {
@@ -44,6 +77,27 @@ func Load() *Config {
 		log.Printf("Warning: Using default JWT secret. Please set JWT_SECRET in environment variables for production!")
 	}
 
+	certExpiryThresholds, err := parseIntList(getEnv("CERT_EXPIRY_THRESHOLDS", "30,14,7,1"))
+	if err != nil {
+		log.Fatalf("Invalid CERT_EXPIRY_THRESHOLDS: %v", err)
+	}
+
+	smtpPort, err := strconv.Atoi(getEnv("SMTP_PORT", "587"))
+	if err != nil {
+		log.Fatalf("Invalid SMTP_PORT: %v", err)
+	}
+
+	smtpTLSMode := strings.ToLower(getEnv("SMTP_TLS", SMTPStartTLS))
+	if smtpTLSMode != SMTPStartTLS && smtpTLSMode != SMTPTLS && smtpTLSMode != SMTPNone {
+		log.Fatalf("Invalid SMTP_TLS: %q (expected starttls, tls or none)", smtpTLSMode)
+	}
+
+	smtpHost := getEnv("SMTP_HOST", "")
+	smtpFrom := getEnv("SMTP_FROM", "")
+	if smtpHost != "" && smtpFrom == "" {
+		log.Fatal("SMTP_FROM is required when SMTP_HOST is set")
+	}
+
 	return &Config{
 		DBHost:        getEnv("DB_HOST", "localhost"),
 		DBPort:        dbPort,
@@ -53,6 +107,20 @@ func Load() *Config {
 		ServerPort:    getEnv("SERVER_PORT", "8080"),
 		JWTSecret:     jwtSecret,
 		TelegramToken: getEnv("TELEGRAM_TOKEN", ""),
+
+		CertExpiryThresholds: certExpiryThresholds,
+
+		SMTP: SMTPConfig{
+			Host:     smtpHost,
+			Port:     smtpPort,
+			Username: getEnv("SMTP_USERNAME", ""),
+			Password: getEnv("SMTP_PASSWORD", ""),
+			From:     smtpFrom,
+			TLSMode:  smtpTLSMode,
+		},
+
+		PagerDutyAPIURL: strings.TrimRight(getEnv("PAGERDUTY_API_URL", "https://events.pagerduty.com"), "/"),
+		OpsgenieAPIURL:  strings.TrimRight(getEnv("OPSGENIE_API_URL", "https://api.opsgenie.com"), "/"),
 	}
 }
 
@@ -62,3 +130,20 @@ func getEnv(key, defaultValue string) string {
 	}
 	return defaultValue
 }
+
+// parseIntList разбирает список положительных чисел через запятую
+func parseIntList(value string) ([]int, error) {
+	var result []int
+	for _, part := range strings.Split(value, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+		n, err := strconv.Atoi(part)
+		if err != nil || n <= 0 {
+			return nil, fmt.Errorf("invalid value %q", part)
+		}
+		result = append(result, n)
+	}
+	return result, nil
+}
@@ -0,0 +1,322 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/aouxes/uptime-monitor/internal/middleware"
+	"github.com/aouxes/uptime-monitor/internal/models"
+	"github.com/aouxes/uptime-monitor/internal/notifier"
+	"github.com/aouxes/uptime-monitor/internal/storage"
+)
+
+type ChannelHandler struct {
+	storage  *storage.Storage
+	registry *notifier.Registry
+}
+
+func NewChannelHandler(storage *storage.Storage, registry *notifier.Registry) *ChannelHandler {
+	return &ChannelHandler{
+		storage:  storage,
+		registry: registry,
+	}
+}
+
+// ChannelRequest — параметры канала уведомлений. Формат config зависит от type.
+// is_default и enabled по умолчанию true.
+type ChannelRequest struct {
+	Type      string          `json:"type"`
+	Name      string          `json:"name"`
+	Config    json.RawMessage `json:"config"`
+	IsDefault *bool           `json:"is_default"`
+	Enabled   *bool           `json:"enabled"`
+}
+
+// decodeChannel читает канал из запроса и проверяет его настройки.
+// При ошибке отвечает клиенту и возвращает nil.
+func (h *ChannelHandler) decodeChannel(ctx context.Context, w http.ResponseWriter, r *http.Request, userID int) *models.NotificationChannel {
+	var req ChannelRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid JSON", http.StatusBadRequest)
+		return nil
+	}
+
+	channel := &models.NotificationChannel{
+		UserID:    userID,
+		Type:      strings.ToLower(strings.TrimSpace(req.Type)),
+		Name:      strings.TrimSpace(req.Name),
+		Config:    req.Config,
+		IsDefault: req.IsDefault == nil || *req.IsDefault,
+		Enabled:   req.Enabled == nil || *req.Enabled,
+	}
+	if len(channel.Config) == 0 || string(channel.Config) == "null" {
+		channel.Config = json.RawMessage("{}")
+	}
+	if channel.Name == "" {
+		channel.Name = channel.Type
+	}
+
+	user, err := h.storage.GetUserByID(ctx, userID)
+	if err != nil {
+		log.Printf("Failed to get user: %v", err)
+		http.Error(w, "Failed to get user", http.StatusInternalServerError)
+		return nil
+	}
+	if user == nil {
+		http.Error(w, "User not found", http.StatusNotFound)
+		return nil
+	}
+
+	// Канал создается так же, как при отправке: так проверяются тип и настройки
+	if _, err := h.registry.New(channel.Type, *user, channel.Config); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return nil
+	}
+
+	return channel
+}
+
+// GetChannels возвращает каналы уведомлений пользователя и поддерживаемые типы
+func (h *ChannelHandler) GetChannels(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	userID, ok := r.Context().Value(middleware.UserIDKey).(int)
+	if !ok {
+		http.Error(w, "User not authenticated", http.StatusUnauthorized)
+		return
+	}
+
+	ctx := context.Background()
+	channels, err := h.storage.GetUserNotificationChannels(ctx, userID)
+	if err != nil {
+		log.Printf("Failed to get notification channels: %v", err)
+		http.Error(w, "Failed to get notification channels", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"channels": channels,
+		"count":    len(channels),
+		"types":    h.registry.Types(),
+	})
+}
+
+// GetChannel возвращает канал уведомлений по ID
+func (h *ChannelHandler) GetChannel(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	userID, ok := r.Context().Value(middleware.UserIDKey).(int)
+	if !ok {
+		http.Error(w, "User not authenticated", http.StatusUnauthorized)
+		return
+	}
+
+	channelID, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil {
+		http.Error(w, "Invalid channel ID", http.StatusBadRequest)
+		return
+	}
+
+	ctx := context.Background()
+	channel, err := h.storage.GetUserNotificationChannel(ctx, userID, channelID)
+	if err != nil {
+		log.Printf("Failed to get notification channel: %v", err)
+		http.Error(w, "Failed to get notification channel", http.StatusInternalServerError)
+		return
+	}
+
+	if channel == nil {
+		http.Error(w, "Notification channel not found", http.StatusNotFound)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(channel)
+}
+
+// CreateChannel создает канал уведомлений пользователя
+func (h *ChannelHandler) CreateChannel(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	userID, ok := r.Context().Value(middleware.UserIDKey).(int)
+	if !ok {
+		http.Error(w, "User not authenticated", http.StatusUnauthorized)
+		return
+	}
+
+	ctx := context.Background()
+	channel := h.decodeChannel(ctx, w, r, userID)
+	if channel == nil {
+		return
+	}
+
+	if err := h.storage.CreateNotificationChannel(ctx, channel); err != nil {
+		log.Printf("Failed to create notification channel: %v", err)
+		http.Error(w, "Failed to create notification channel", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(channel)
+}
+
+// UpdateChannel заменяет параметры канала уведомлений
+func (h *ChannelHandler) UpdateChannel(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPut {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	userID, ok := r.Context().Value(middleware.UserIDKey).(int)
+	if !ok {
+		http.Error(w, "User not authenticated", http.StatusUnauthorized)
+		return
+	}
+
+	channelID, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil {
+		http.Error(w, "Invalid channel ID", http.StatusBadRequest)
+		return
+	}
+
+	ctx := context.Background()
+	channel := h.decodeChannel(ctx, w, r, userID)
+	if channel == nil {
+		return
+	}
+	channel.ID = channelID
+
+	updated, err := h.storage.UpdateNotificationChannel(ctx, channel)
+	if err != nil {
+		log.Printf("Failed to update notification channel: %v", err)
+		http.Error(w, "Failed to update notification channel", http.StatusInternalServerError)
+		return
+	}
+
+	if !updated {
+		http.Error(w, "Notification channel not found", http.StatusNotFound)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(channel)
+}
+
+// DeleteChannel удаляет канал уведомлений. Сайты, направленные только в него,
+// возвращаются к каналам по умолчанию.
+func (h *ChannelHandler) DeleteChannel(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodDelete {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	userID, ok := r.Context().Value(middleware.UserIDKey).(int)
+	if !ok {
+		http.Error(w, "User not authenticated", http.StatusUnauthorized)
+		return
+	}
+
+	channelID, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil {
+		http.Error(w, "Invalid channel ID", http.StatusBadRequest)
+		return
+	}
+
+	ctx := context.Background()
+	deleted, err := h.storage.DeleteNotificationChannel(ctx, userID, channelID)
+	if err != nil {
+		log.Printf("Failed to delete notification channel: %v", err)
+		http.Error(w, "Failed to delete notification channel", http.StatusInternalServerError)
+		return
+	}
+
+	if !deleted {
+		http.Error(w, "Notification channel not found", http.StatusNotFound)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"message":    "Notification channel deleted successfully",
+		"channel_id": channelID,
+	})
+}
+
+// testSendTimeout ограничивает отправку тестового уведомления вместе с повторами
+const testSendTimeout = time.Minute
+
+// TestChannel отправляет в канал тестовое уведомление
+func (h *ChannelHandler) TestChannel(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	userID, ok := r.Context().Value(middleware.UserIDKey).(int)
+	if !ok {
+		http.Error(w, "User not authenticated", http.StatusUnauthorized)
+		return
+	}
+
+	channelID, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil {
+		http.Error(w, "Invalid channel ID", http.StatusBadRequest)
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), testSendTimeout)
+	defer cancel()
+
+	stored, err := h.storage.GetUserNotificationChannel(ctx, userID, channelID)
+	if err != nil {
+		log.Printf("Failed to get notification channel: %v", err)
+		http.Error(w, "Failed to get notification channel", http.StatusInternalServerError)
+		return
+	}
+
+	if stored == nil {
+		http.Error(w, "Notification channel not found", http.StatusNotFound)
+		return
+	}
+
+	user, err := h.storage.GetUserByID(ctx, userID)
+	if err != nil || user == nil {
+		log.Printf("Failed to get user %d: %v", userID, err)
+		http.Error(w, "Failed to get user", http.StatusInternalServerError)
+		return
+	}
+
+	channel, err := h.registry.New(stored.Type, *user, stored.Config)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	if err := channel.Send(ctx, notifier.NewTestEvent()); err != nil {
+		log.Printf("Test notification to channel %d failed: %v", channelID, err)
+		http.Error(w, "Failed to send test notification: "+err.Error(), http.StatusBadGateway)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"message":    "Test notification sent successfully",
+		"channel_id": channelID,
+	})
+}
@@ -0,0 +1,284 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/aouxes/uptime-monitor/internal/middleware"
+	"github.com/aouxes/uptime-monitor/internal/models"
+	"github.com/aouxes/uptime-monitor/internal/notifier"
+	"github.com/aouxes/uptime-monitor/internal/storage"
+)
+
+type EscalationHandler struct {
+	storage *storage.Storage
+}
+
+func NewEscalationHandler(storage *storage.Storage) *EscalationHandler {
+	return &EscalationHandler{storage: storage}
+}
+
+// EscalationPolicyRequest — параметры политики эскалации.
+// Сайт из site_ids, привязанный к другой политике, переходит в эту.
+type EscalationPolicyRequest struct {
+	Name    string                  `json:"name"`
+	Steps   []models.EscalationStep `json:"steps"`
+	Repeat  bool                    `json:"repeat"`
+	SiteIDs []int                   `json:"site_ids"`
+}
+
+// decodePolicy читает политику эскалации из запроса и проверяет, что ее сайты и каналы
+// принадлежат пользователю, а указанные в шагах пользователи существуют.
+// При ошибке отвечает клиенту и возвращает nil.
+func (h *EscalationHandler) decodePolicy(ctx context.Context, w http.ResponseWriter, r *http.Request, userID int) *models.EscalationPolicy {
+	var req EscalationPolicyRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid JSON", http.StatusBadRequest)
+		return nil
+	}
+
+	policy := &models.EscalationPolicy{
+		UserID:  userID,
+		Name:    strings.TrimSpace(req.Name),
+		Steps:   req.Steps,
+		Repeat:  req.Repeat,
+		SiteIDs: req.SiteIDs,
+	}
+
+	if err := notifier.ValidateEscalationPolicy(policy); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return nil
+	}
+
+	message, err := h.checkReferences(ctx, policy)
+	if err != nil {
+		log.Printf("Failed to check escalation policy: %v", err)
+		http.Error(w, "Failed to check escalation policy", http.StatusInternalServerError)
+		return nil
+	}
+	if message != "" {
+		http.Error(w, message, http.StatusBadRequest)
+		return nil
+	}
+
+	return policy
+}
+
+// checkReferences проверяет, что сайты и каналы политики принадлежат ее владельцу.
+// Возвращает описание первой ошибки или пустую строку.
+func (h *EscalationHandler) checkReferences(ctx context.Context, policy *models.EscalationPolicy) (string, error) {
+	sites, err := h.storage.GetUserSites(ctx, policy.UserID)
+	if err != nil {
+		return "", err
+	}
+
+	ownedSites := make(map[int]bool, len(sites))
+	for _, site := range sites {
+		ownedSites[site.ID] = true
+	}
+	for _, siteID := range policy.SiteIDs {
+		if !ownedSites[siteID] {
+			return fmt.Sprintf("Site %d not found", siteID), nil
+		}
+	}
+
+	channels, err := h.storage.GetUserNotificationChannels(ctx, policy.UserID)
+	if err != nil {
+		return "", err
+	}
+
+	ownedChannels := make(map[int]bool, len(channels))
+	for _, channel := range channels {
+		ownedChannels[channel.ID] = true
+	}
+
+	for _, step := range policy.Steps {
+		for _, channelID := range step.ChannelIDs {
+			if !ownedChannels[channelID] {
+				return fmt.Sprintf("Notification channel %d not found", channelID), nil
+			}
+		}
+	}
+
+	return "", nil
+}
+
+// GetEscalationPolicies возвращает политики эскалации пользователя
+func (h *EscalationHandler) GetEscalationPolicies(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	userID, ok := r.Context().Value(middleware.UserIDKey).(int)
+	if !ok {
+		http.Error(w, "User not authenticated", http.StatusUnauthorized)
+		return
+	}
+
+	ctx := context.Background()
+	policies, err := h.storage.GetUserEscalationPolicies(ctx, userID)
+	if err != nil {
+		log.Printf("Failed to get escalation policies: %v", err)
+		http.Error(w, "Failed to get escalation policies", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"escalation_policies": policies,
+		"count":               len(policies),
+	})
+}
+
+// GetEscalationPolicy возвращает политику эскалации по ID
+func (h *EscalationHandler) GetEscalationPolicy(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	userID, ok := r.Context().Value(middleware.UserIDKey).(int)
+	if !ok {
+		http.Error(w, "User not authenticated", http.StatusUnauthorized)
+		return
+	}
+
+	policyID, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil {
+		http.Error(w, "Invalid escalation policy ID", http.StatusBadRequest)
+		return
+	}
+
+	ctx := context.Background()
+	policy, err := h.storage.GetUserEscalationPolicy(ctx, userID, policyID)
+	if err != nil {
+		log.Printf("Failed to get escalation policy: %v", err)
+		http.Error(w, "Failed to get escalation policy", http.StatusInternalServerError)
+		return
+	}
+
+	if policy == nil {
+		http.Error(w, "Escalation policy not found", http.StatusNotFound)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(policy)
+}
+
+// CreateEscalationPolicy создает политику эскалации для сайтов пользователя
+func (h *EscalationHandler) CreateEscalationPolicy(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	userID, ok := r.Context().Value(middleware.UserIDKey).(int)
+	if !ok {
+		http.Error(w, "User not authenticated", http.StatusUnauthorized)
+		return
+	}
+
+	ctx := context.Background()
+	policy := h.decodePolicy(ctx, w, r, userID)
+	if policy == nil {
+		return
+	}
+
+	if err := h.storage.CreateEscalationPolicy(ctx, policy); err != nil {
+		log.Printf("Failed to create escalation policy: %v", err)
+		http.Error(w, "Failed to create escalation policy", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(policy)
+}
+
+// UpdateEscalationPolicy заменяет шаги и сайты политики эскалации
+func (h *EscalationHandler) UpdateEscalationPolicy(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPut {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	userID, ok := r.Context().Value(middleware.UserIDKey).(int)
+	if !ok {
+		http.Error(w, "User not authenticated", http.StatusUnauthorized)
+		return
+	}
+
+	policyID, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil {
+		http.Error(w, "Invalid escalation policy ID", http.StatusBadRequest)
+		return
+	}
+
+	ctx := context.Background()
+	policy := h.decodePolicy(ctx, w, r, userID)
+	if policy == nil {
+		return
+	}
+	policy.ID = policyID
+
+	updated, err := h.storage.UpdateEscalationPolicy(ctx, policy)
+	if err != nil {
+		log.Printf("Failed to update escalation policy: %v", err)
+		http.Error(w, "Failed to update escalation policy", http.StatusInternalServerError)
+		return
+	}
+
+	if !updated {
+		http.Error(w, "Escalation policy not found", http.StatusNotFound)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(policy)
+}
+
+// DeleteEscalationPolicy удаляет политику эскалации. Эскалация открытых инцидентов ее сайтов прекращается.
+func (h *EscalationHandler) DeleteEscalationPolicy(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodDelete {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	userID, ok := r.Context().Value(middleware.UserIDKey).(int)
+	if !ok {
+		http.Error(w, "User not authenticated", http.StatusUnauthorized)
+		return
+	}
+
+	policyID, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil {
+		http.Error(w, "Invalid escalation policy ID", http.StatusBadRequest)
+		return
+	}
+
+	ctx := context.Background()
+	deleted, err := h.storage.DeleteEscalationPolicy(ctx, userID, policyID)
+	if err != nil {
+		log.Printf("Failed to delete escalation policy: %v", err)
+		http.Error(w, "Failed to delete escalation policy", http.StatusInternalServerError)
+		return
+	}
+
+	if !deleted {
+		http.Error(w, "Escalation policy not found", http.StatusNotFound)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"message":              "Escalation policy deleted successfully",
+		"escalation_policy_id": policyID,
+	})
+}
@@ -0,0 +1,284 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/aouxes/uptime-monitor/internal/middleware"
+	"github.com/aouxes/uptime-monitor/internal/models"
+	"github.com/aouxes/uptime-monitor/internal/notifier"
+	"github.com/aouxes/uptime-monitor/internal/storage"
+)
+
+type IncidentHandler struct {
+	storage *storage.Storage
+}
+
+func NewIncidentHandler(storage *storage.Storage) *IncidentHandler {
+	return &IncidentHandler{storage: storage}
+}
+
+// GetIncidents возвращает инциденты пользователя.
+// Фильтры: site_id, status=open|resolved, from и to (RFC3339, по времени начала), limit.
+func (h *IncidentHandler) GetIncidents(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	userID, ok := r.Context().Value(middleware.UserIDKey).(int)
+	if !ok {
+		http.Error(w, "User not authenticated", http.StatusUnauthorized)
+		return
+	}
+
+	filter, err := parseIncidentFilter(r.URL.Query())
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	ctx := context.Background()
+	incidents, err := h.storage.GetUserIncidents(ctx, userID, filter)
+	if err != nil {
+		log.Printf("Failed to get incidents: %v", err)
+		http.Error(w, "Failed to get incidents", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"incidents": incidents,
+		"count":     len(incidents),
+	})
+}
+
+// GetIncident возвращает инцидент с хронологией событий
+func (h *IncidentHandler) GetIncident(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	userID, ok := r.Context().Value(middleware.UserIDKey).(int)
+	if !ok {
+		http.Error(w, "User not authenticated", http.StatusUnauthorized)
+		return
+	}
+
+	incidentID, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil {
+		http.Error(w, "Invalid incident ID", http.StatusBadRequest)
+		return
+	}
+
+	ctx := context.Background()
+	incident, err := h.storage.GetUserIncident(ctx, userID, incidentID)
+	if err != nil {
+		log.Printf("Failed to get incident: %v", err)
+		http.Error(w, "Failed to get incident", http.StatusInternalServerError)
+		return
+	}
+
+	if incident == nil {
+		http.Error(w, "Incident not found", http.StatusNotFound)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(incident)
+}
+
+// maxIncidentNoteLength — максимальная длина заметки к инциденту
+const maxIncidentNoteLength = 2000
+
+type AddIncidentNoteRequest struct {
+	Text string `json:"text"`
+}
+
+// AcknowledgeIncident подтверждает инцидент: фиксирует, кто и когда взял его в работу
+func (h *IncidentHandler) AcknowledgeIncident(w http.ResponseWriter, r *http.Request) {
+	h.changeIncident(w, r, func(ctx context.Context, userID, incidentID int, username string) (bool, error) {
+		return h.storage.AcknowledgeIncident(ctx, userID, incidentID, username, "acknowledged by "+username)
+	}, "Incident is already acknowledged or resolved")
+}
+
+// ResolveIncident вручную закрывает инцидент
+func (h *IncidentHandler) ResolveIncident(w http.ResponseWriter, r *http.Request) {
+	h.changeIncident(w, r, func(ctx context.Context, userID, incidentID int, username string) (bool, error) {
+		notification := notifier.IncidentResolvedNotification(incidentID)
+		return h.storage.ResolveUserIncident(ctx, userID, incidentID, "resolved manually by "+username, notification)
+	}, "Incident is already resolved")
+}
+
+// changeIncident выполняет действие над инцидентом пользователя и возвращает обновленный инцидент.
+// Если действие не применилось к существующему инциденту, отвечает 409 с conflictMessage.
+func (h *IncidentHandler) changeIncident(w http.ResponseWriter, r *http.Request,
+	change func(ctx context.Context, userID, incidentID int, username string) (bool, error), conflictMessage string) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	userID, ok := r.Context().Value(middleware.UserIDKey).(int)
+	if !ok {
+		http.Error(w, "User not authenticated", http.StatusUnauthorized)
+		return
+	}
+
+	incidentID, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil {
+		http.Error(w, "Invalid incident ID", http.StatusBadRequest)
+		return
+	}
+
+	ctx := context.Background()
+	user, err := h.storage.GetUserByID(ctx, userID)
+	if err != nil || user == nil {
+		log.Printf("Failed to get user %d: %v", userID, err)
+		http.Error(w, "Failed to get user", http.StatusInternalServerError)
+		return
+	}
+
+	changed, err := change(ctx, userID, incidentID, user.Username)
+	if err != nil {
+		log.Printf("Failed to update incident %d: %v", incidentID, err)
+		http.Error(w, "Failed to update incident", http.StatusInternalServerError)
+		return
+	}
+
+	incident, err := h.storage.GetUserIncident(ctx, userID, incidentID)
+	if err != nil {
+		log.Printf("Failed to get incident: %v", err)
+		http.Error(w, "Failed to get incident", http.StatusInternalServerError)
+		return
+	}
+
+	if incident == nil {
+		http.Error(w, "Incident not found", http.StatusNotFound)
+		return
+	}
+
+	if !changed {
+		http.Error(w, conflictMessage, http.StatusConflict)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(incident)
+}
+
+// AddIncidentNote добавляет текстовую заметку к инциденту
+func (h *IncidentHandler) AddIncidentNote(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	userID, ok := r.Context().Value(middleware.UserIDKey).(int)
+	if !ok {
+		http.Error(w, "User not authenticated", http.StatusUnauthorized)
+		return
+	}
+
+	incidentID, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil {
+		http.Error(w, "Invalid incident ID", http.StatusBadRequest)
+		return
+	}
+
+	var req AddIncidentNoteRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid JSON", http.StatusBadRequest)
+		return
+	}
+
+	text := strings.TrimSpace(req.Text)
+	if text == "" || len([]rune(text)) > maxIncidentNoteLength {
+		http.Error(w, fmt.Sprintf("Note text must be 1-%d characters", maxIncidentNoteLength), http.StatusBadRequest)
+		return
+	}
+
+	ctx := context.Background()
+	incident, err := h.storage.GetUserIncident(ctx, userID, incidentID)
+	if err != nil {
+		log.Printf("Failed to get incident: %v", err)
+		http.Error(w, "Failed to get incident", http.StatusInternalServerError)
+		return
+	}
+
+	if incident == nil {
+		http.Error(w, "Incident not found", http.StatusNotFound)
+		return
+	}
+
+	user, err := h.storage.GetUserByID(ctx, userID)
+	if err != nil || user == nil {
+		log.Printf("Failed to get user %d: %v", userID, err)
+		http.Error(w, "Failed to get user", http.StatusInternalServerError)
+		return
+	}
+
+	note := &models.IncidentNote{Author: user.Username, Text: text}
+	if err := h.storage.CreateIncidentNote(ctx, incidentID, note); err != nil {
+		log.Printf("Failed to create incident note: %v", err)
+		http.Error(w, "Failed to add note", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(note)
+}
+
+func parseIncidentFilter(query url.Values) (models.IncidentFilter, error) {
+	filter := models.IncidentFilter{Limit: 50}
+
+	if siteID := query.Get("site_id"); siteID != "" {
+		id, err := strconv.Atoi(siteID)
+		if err != nil || id <= 0 {
+			return filter, fmt.Errorf("Invalid site_id")
+		}
+		filter.SiteID = id
+	}
+
+	switch status := query.Get("status"); status {
+	case "", models.IncidentStatusOpen, models.IncidentStatusResolved:
+		filter.Status = status
+	default:
+		return filter, fmt.Errorf("Invalid status, expected open or resolved")
+	}
+
+	if from := query.Get("from"); from != "" {
+		t, err := time.Parse(time.RFC3339, from)
+		if err != nil {
+			return filter, fmt.Errorf("Invalid from, expected RFC3339")
+		}
+		filter.From = &t
+	}
+
+	if to := query.Get("to"); to != "" {
+		t, err := time.Parse(time.RFC3339, to)
+		if err != nil {
+			return filter, fmt.Errorf("Invalid to, expected RFC3339")
+		}
+		filter.To = &t
+	}
+
+	if limit := query.Get("limit"); limit != "" {
+		n, err := strconv.Atoi(limit)
+		if err != nil || n <= 0 || n > 500 {
+			return filter, fmt.Errorf("Invalid limit")
+		}
+		filter.Limit = n
+	}
+
+	return filter, nil
+}
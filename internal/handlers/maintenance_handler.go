@@ -0,0 +1,287 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/aouxes/uptime-monitor/internal/checker"
+	"github.com/aouxes/uptime-monitor/internal/middleware"
+	"github.com/aouxes/uptime-monitor/internal/models"
+	"github.com/aouxes/uptime-monitor/internal/storage"
+)
+
+type MaintenanceHandler struct {
+	storage *storage.Storage
+}
+
+func NewMaintenanceHandler(storage *storage.Storage) *MaintenanceHandler {
+	return &MaintenanceHandler{storage: storage}
+}
+
+// MaintenanceWindowRequest — параметры окна обслуживания.
+// Для type=once задаются starts_at и ends_at, для weekly — weekdays, start_time и duration_minutes,
+// для cron — cron и duration_minutes. Время weekly и cron окон считается в timezone (по умолчанию UTC).
+type MaintenanceWindowRequest struct {
+	Name            string     `json:"name"`
+	Type            string     `json:"type"`
+	StartsAt        *time.Time `json:"starts_at"`
+	EndsAt          *time.Time `json:"ends_at"`
+	Weekdays        []int      `json:"weekdays"`
+	StartTime       string     `json:"start_time"`
+	Cron            string     `json:"cron"`
+	DurationMinutes int        `json:"duration_minutes"`
+	Timezone        string     `json:"timezone"`
+	SiteIDs         []int      `json:"site_ids"`
+}
+
+// window проверяет запрос и собирает по нему окно обслуживания пользователя
+func (req MaintenanceWindowRequest) window(userID int) (*models.MaintenanceWindow, error) {
+	window := &models.MaintenanceWindow{
+		UserID:          userID,
+		Name:            req.Name,
+		Type:            req.Type,
+		StartsAt:        req.StartsAt,
+		EndsAt:          req.EndsAt,
+		Weekdays:        req.Weekdays,
+		StartTime:       req.StartTime,
+		Cron:            req.Cron,
+		DurationMinutes: req.DurationMinutes,
+		Timezone:        req.Timezone,
+		SiteIDs:         req.SiteIDs,
+	}
+
+	if err := checker.ValidateMaintenanceWindow(window); err != nil {
+		return nil, err
+	}
+
+	return window, nil
+}
+
+// unknownSiteID возвращает первый ID из siteIDs, которого нет среди сайтов пользователя (0 — все найдены)
+func (h *MaintenanceHandler) unknownSiteID(ctx context.Context, userID int, siteIDs []int) (int, error) {
+	sites, err := h.storage.GetUserSites(ctx, userID)
+	if err != nil {
+		return 0, err
+	}
+
+	owned := make(map[int]bool, len(sites))
+	for _, site := range sites {
+		owned[site.ID] = true
+	}
+	for _, siteID := range siteIDs {
+		if !owned[siteID] {
+			return siteID, nil
+		}
+	}
+
+	return 0, nil
+}
+
+// decodeWindow читает окно обслуживания из запроса и проверяет, что его сайты принадлежат пользователю.
+// При ошибке отвечает клиенту и возвращает nil.
+func (h *MaintenanceHandler) decodeWindow(ctx context.Context, w http.ResponseWriter, r *http.Request, userID int) *models.MaintenanceWindow {
+	var req MaintenanceWindowRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid JSON", http.StatusBadRequest)
+		return nil
+	}
+
+	window, err := req.window(userID)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return nil
+	}
+
+	siteID, err := h.unknownSiteID(ctx, userID, window.SiteIDs)
+	if err != nil {
+		log.Printf("Failed to get user sites: %v", err)
+		http.Error(w, "Failed to get sites", http.StatusInternalServerError)
+		return nil
+	}
+	if siteID != 0 {
+		http.Error(w, fmt.Sprintf("Site %d not found", siteID), http.StatusBadRequest)
+		return nil
+	}
+
+	return window
+}
+
+// GetMaintenanceWindows возвращает окна обслуживания пользователя
+func (h *MaintenanceHandler) GetMaintenanceWindows(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	userID, ok := r.Context().Value(middleware.UserIDKey).(int)
+	if !ok {
+		http.Error(w, "User not authenticated", http.StatusUnauthorized)
+		return
+	}
+
+	ctx := context.Background()
+	windows, err := h.storage.GetUserMaintenanceWindows(ctx, userID)
+	if err != nil {
+		log.Printf("Failed to get maintenance windows: %v", err)
+		http.Error(w, "Failed to get maintenance windows", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"maintenance_windows": windows,
+		"count":               len(windows),
+	})
+}
+
+// GetMaintenanceWindow возвращает окно обслуживания по ID
+func (h *MaintenanceHandler) GetMaintenanceWindow(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	userID, ok := r.Context().Value(middleware.UserIDKey).(int)
+	if !ok {
+		http.Error(w, "User not authenticated", http.StatusUnauthorized)
+		return
+	}
+
+	windowID, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil {
+		http.Error(w, "Invalid maintenance window ID", http.StatusBadRequest)
+		return
+	}
+
+	ctx := context.Background()
+	window, err := h.storage.GetUserMaintenanceWindow(ctx, userID, windowID)
+	if err != nil {
+		log.Printf("Failed to get maintenance window: %v", err)
+		http.Error(w, "Failed to get maintenance window", http.StatusInternalServerError)
+		return
+	}
+
+	if window == nil {
+		http.Error(w, "Maintenance window not found", http.StatusNotFound)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(window)
+}
+
+// CreateMaintenanceWindow создает окно обслуживания для сайтов пользователя
+func (h *MaintenanceHandler) CreateMaintenanceWindow(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	userID, ok := r.Context().Value(middleware.UserIDKey).(int)
+	if !ok {
+		http.Error(w, "User not authenticated", http.StatusUnauthorized)
+		return
+	}
+
+	ctx := context.Background()
+	window := h.decodeWindow(ctx, w, r, userID)
+	if window == nil {
+		return
+	}
+
+	if err := h.storage.CreateMaintenanceWindow(ctx, window); err != nil {
+		log.Printf("Failed to create maintenance window: %v", err)
+		http.Error(w, "Failed to create maintenance window", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(window)
+}
+
+// UpdateMaintenanceWindow заменяет параметры и сайты окна обслуживания
+func (h *MaintenanceHandler) UpdateMaintenanceWindow(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPut {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	userID, ok := r.Context().Value(middleware.UserIDKey).(int)
+	if !ok {
+		http.Error(w, "User not authenticated", http.StatusUnauthorized)
+		return
+	}
+
+	windowID, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil {
+		http.Error(w, "Invalid maintenance window ID", http.StatusBadRequest)
+		return
+	}
+
+	ctx := context.Background()
+	window := h.decodeWindow(ctx, w, r, userID)
+	if window == nil {
+		return
+	}
+	window.ID = windowID
+
+	updated, err := h.storage.UpdateMaintenanceWindow(ctx, window)
+	if err != nil {
+		log.Printf("Failed to update maintenance window: %v", err)
+		http.Error(w, "Failed to update maintenance window", http.StatusInternalServerError)
+		return
+	}
+
+	if !updated {
+		http.Error(w, "Maintenance window not found", http.StatusNotFound)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(window)
+}
+
+// DeleteMaintenanceWindow удаляет окно обслуживания
+func (h *MaintenanceHandler) DeleteMaintenanceWindow(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodDelete {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	userID, ok := r.Context().Value(middleware.UserIDKey).(int)
+	if !ok {
+		http.Error(w, "User not authenticated", http.StatusUnauthorized)
+		return
+	}
+
+	windowID, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil {
+		http.Error(w, "Invalid maintenance window ID", http.StatusBadRequest)
+		return
+	}
+
+	ctx := context.Background()
+	deleted, err := h.storage.DeleteMaintenanceWindow(ctx, userID, windowID)
+	if err != nil {
+		log.Printf("Failed to delete maintenance window: %v", err)
+		http.Error(w, "Failed to delete maintenance window", http.StatusInternalServerError)
+		return
+	}
+
+	if !deleted {
+		http.Error(w, "Maintenance window not found", http.StatusNotFound)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"message":               "Maintenance window deleted successfully",
+		"maintenance_window_id": windowID,
+	})
+}
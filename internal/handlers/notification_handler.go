@@ -0,0 +1,96 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"log"
+	"net/http"
+	"strconv"
+
+	"github.com/aouxes/uptime-monitor/internal/middleware"
+	"github.com/aouxes/uptime-monitor/internal/storage"
+)
+
+type NotificationHandler struct {
+	storage *storage.Storage
+}
+
+func NewNotificationHandler(storage *storage.Storage) *NotificationHandler {
+	return &NotificationHandler{storage: storage}
+}
+
+// GetFailedNotifications возвращает уведомления, доставить которые не удалось
+// после всех повторов. Параметр limit — до 500, по умолчанию 50.
+func (h *NotificationHandler) GetFailedNotifications(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	userID, ok := r.Context().Value(middleware.UserIDKey).(int)
+	if !ok {
+		http.Error(w, "User not authenticated", http.StatusUnauthorized)
+		return
+	}
+
+	limit := 50
+	if value := r.URL.Query().Get("limit"); value != "" {
+		n, err := strconv.Atoi(value)
+		if err != nil || n <= 0 || n > 500 {
+			http.Error(w, "Invalid limit", http.StatusBadRequest)
+			return
+		}
+		limit = n
+	}
+
+	ctx := context.Background()
+	notifications, err := h.storage.GetUserFailedNotifications(ctx, userID, limit)
+	if err != nil {
+		log.Printf("Failed to get failed notifications: %v", err)
+		http.Error(w, "Failed to get failed notifications", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"notifications": notifications,
+		"count":         len(notifications),
+	})
+}
+
+// RetryNotification возвращает недоставленное уведомление в очередь.
+// Каналы, в которые оно уже доставлено, не повторяются.
+func (h *NotificationHandler) RetryNotification(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	userID, ok := r.Context().Value(middleware.UserIDKey).(int)
+	if !ok {
+		http.Error(w, "User not authenticated", http.StatusUnauthorized)
+		return
+	}
+
+	notificationID, err := strconv.ParseInt(r.PathValue("id"), 10, 64)
+	if err != nil {
+		http.Error(w, "Invalid notification ID", http.StatusBadRequest)
+		return
+	}
+
+	ctx := context.Background()
+	notification, err := h.storage.RetryUserNotification(ctx, userID, notificationID)
+	if err != nil {
+		log.Printf("Failed to retry notification: %v", err)
+		http.Error(w, "Failed to retry notification", http.StatusInternalServerError)
+		return
+	}
+
+	if notification == nil {
+		http.Error(w, "Failed notification not found", http.StatusNotFound)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(notification)
+}
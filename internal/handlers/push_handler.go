@@ -0,0 +1,77 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"log"
+	"net/http"
+	"strings"
+
+	"github.com/aouxes/uptime-monitor/internal/checker"
+	"github.com/aouxes/uptime-monitor/internal/storage"
+)
+
+// maxPushMessageLength — максимальная длина сообщения о сбое в пинге
+const maxPushMessageLength = 500
+
+type PushHandler struct {
+	storage *storage.Storage
+	checker *checker.Checker
+}
+
+func NewPushHandler(storage *storage.Storage, checker *checker.Checker) *PushHandler {
+	return &PushHandler{
+		storage: storage,
+		checker: checker,
+	}
+}
+
+// Push принимает пинг push монитора: POST /api/push/{token}[?status=fail&msg=...]
+func (h *PushHandler) Push(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	token := r.PathValue("token")
+	if token == "" {
+		http.Error(w, "Monitor not found", http.StatusNotFound)
+		return
+	}
+
+	var failed bool
+	switch strings.ToLower(r.URL.Query().Get("status")) {
+	case "", "ok", "up":
+	case "fail", "down":
+		failed = true
+	default:
+		http.Error(w, "Invalid status, expected ok or fail", http.StatusBadRequest)
+		return
+	}
+
+	message := r.URL.Query().Get("msg")
+	if runes := []rune(message); len(runes) > maxPushMessageLength {
+		message = string(runes[:maxPushMessageLength])
+	}
+
+	ctx := context.Background()
+	site, err := h.storage.GetSiteByPushToken(ctx, token)
+	if err != nil {
+		log.Printf("Failed to get push monitor: %v", err)
+		http.Error(w, "Failed to record ping", http.StatusInternalServerError)
+		return
+	}
+
+	if site == nil {
+		http.Error(w, "Monitor not found", http.StatusNotFound)
+		return
+	}
+
+	result := h.checker.RecordPush(ctx, site, failed, message)
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"message": "Ping received",
+		"status":  result.Status,
+	})
+}
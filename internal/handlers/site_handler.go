@@ -2,10 +2,13 @@ package handlers
 
 import (
 	"context"
+	"crypto/rand"
+	"encoding/hex"
 	"encoding/json"
 	"fmt"
 	"log"
 	"net/http"
+	"strings"
 	"sync"
 	"sync/atomic"
 	"time"
@@ -13,24 +16,152 @@ import (
 	"github.com/aouxes/uptime-monitor/internal/checker"
 	"github.com/aouxes/uptime-monitor/internal/middleware"
 	"github.com/aouxes/uptime-monitor/internal/models"
-	"github.com/aouxes/uptime-monitor/internal/notifier"
 	"github.com/aouxes/uptime-monitor/internal/storage"
+	"github.com/aouxes/uptime-monitor/internal/utils"
 )
 
 type SiteHandler struct {
-	storage  *storage.Storage
-	notifier *notifier.Notifier
+	storage *storage.Storage
+	checker *checker.Checker
 }
 
-func NewSiteHandler(storage *storage.Storage, notifier *notifier.Notifier) *SiteHandler {
+func NewSiteHandler(storage *storage.Storage, checker *checker.Checker) *SiteHandler {
 	return &SiteHandler{
-		storage:  storage,
-		notifier: notifier,
+		storage: storage,
+		checker: checker,
 	}
 }
 
+// SiteSettingsRequest — настраиваемые поля сайта. Не указанные поля (nil) не изменяются.
+type SiteSettingsRequest struct {
+	URL                  *string                 `json:"url,omitempty"`
+	MonitorType          *string                 `json:"monitor_type,omitempty"`
+	IntervalSeconds      *int                    `json:"interval_seconds,omitempty"`
+	HTTPMethod           *string                 `json:"http_method,omitempty"`
+	HTTPHeaders          map[string]string       `json:"http_headers,omitempty"`
+	HTTPBody             *string                 `json:"http_body,omitempty"`
+	ExpectedStatusCodes  *string                 `json:"expected_status_codes,omitempty"`
+	FollowRedirects      *bool                   `json:"follow_redirects,omitempty"`
+	MaxRedirects         *int                    `json:"max_redirects,omitempty"`
+	Keyword              *string                 `json:"keyword,omitempty"`
+	KeywordMode          *string                 `json:"keyword_mode,omitempty"`
+	JSONAssertions       *[]models.JSONAssertion `json:"json_assertions,omitempty"`
+	TCPSend              *string                 `json:"tcp_send,omitempty"`
+	TCPExpect            *string                 `json:"tcp_expect,omitempty"`
+	DNSRecordType        *string                 `json:"dns_record_type,omitempty"`
+	DNSResolver          *string                 `json:"dns_resolver,omitempty"`
+	DNSExpected          *[]string               `json:"dns_expected,omitempty"`
+	PushGraceSeconds     *int                    `json:"push_grace_seconds,omitempty"`
+	FailThreshold        *int                    `json:"fail_threshold,omitempty"`
+	RecoverThreshold     *int                    `json:"recover_threshold,omitempty"`
+	RetryIntervalSeconds *int                    `json:"retry_interval_seconds,omitempty"`
+	ParentSiteID         *int                    `json:"parent_site_id,omitempty"` // 0 — убрать родителя
+	// Каналы уведомлений сайта. Пустой список — каналы пользователя по умолчанию
+	NotificationChannelIDs *[]int `json:"notification_channel_ids,omitempty"`
+}
+
+// apply применяет указанные настройки к сайту и проверяет результат
+func (req *SiteSettingsRequest) apply(site *models.Site) error {
+	if req.URL != nil {
+		site.URL = strings.TrimSpace(*req.URL)
+	}
+	if req.MonitorType != nil {
+		site.MonitorType = strings.ToLower(*req.MonitorType)
+	}
+
+	if req.IntervalSeconds != nil {
+		if err := utils.ValidateCheckInterval(*req.IntervalSeconds); err != nil {
+			return err
+		}
+		site.IntervalSeconds = *req.IntervalSeconds
+	}
+
+	if req.HTTPMethod != nil {
+		site.HTTPMethod = strings.ToUpper(*req.HTTPMethod)
+	}
+	if req.HTTPHeaders != nil {
+		site.HTTPHeaders = req.HTTPHeaders
+	}
+	if req.HTTPBody != nil {
+		site.HTTPBody = *req.HTTPBody
+	}
+	if req.ExpectedStatusCodes != nil {
+		site.ExpectedStatusCodes = *req.ExpectedStatusCodes
+	}
+	if req.FollowRedirects != nil {
+		site.FollowRedirects = *req.FollowRedirects
+	}
+	if req.MaxRedirects != nil {
+		site.MaxRedirects = *req.MaxRedirects
+	}
+	if req.Keyword != nil {
+		site.Keyword = *req.Keyword
+	}
+	if req.KeywordMode != nil {
+		site.KeywordMode = *req.KeywordMode
+	}
+	if req.JSONAssertions != nil {
+		site.JSONAssertions = *req.JSONAssertions
+	}
+	if req.TCPSend != nil {
+		site.TCPSend = *req.TCPSend
+	}
+	if req.TCPExpect != nil {
+		site.TCPExpect = *req.TCPExpect
+	}
+	if req.DNSRecordType != nil {
+		site.DNSRecordType = strings.ToUpper(*req.DNSRecordType)
+	}
+	if req.DNSResolver != nil {
+		site.DNSResolver = strings.TrimSpace(*req.DNSResolver)
+	}
+	if req.DNSExpected != nil {
+		site.DNSExpected = *req.DNSExpected
+	}
+	if req.PushGraceSeconds != nil {
+		site.PushGraceSeconds = *req.PushGraceSeconds
+	}
+	if req.FailThreshold != nil {
+		site.FailThreshold = *req.FailThreshold
+	}
+	if req.RecoverThreshold != nil {
+		site.RecoverThreshold = *req.RecoverThreshold
+	}
+	if req.RetryIntervalSeconds != nil {
+		site.RetryIntervalSeconds = *req.RetryIntervalSeconds
+	}
+	if req.ParentSiteID != nil {
+		site.ParentSiteID = *req.ParentSiteID
+	}
+	if req.NotificationChannelIDs != nil {
+		site.NotificationChannelIDs = *req.NotificationChannelIDs
+	}
+
+	// Токен push монитора выдается один раз и сохраняется при изменении настроек
+	if site.MonitorType != models.MonitorPush {
+		site.PushToken = ""
+	} else if site.PushToken == "" {
+		token, err := generatePushToken()
+		if err != nil {
+			return err
+		}
+		site.PushToken = token
+	}
+
+	return checker.ValidateSite(site)
+}
+
+// generatePushToken создает секретный токен для URL push монитора
+func generatePushToken() (string, error) {
+	bytes := make([]byte, 16)
+	if _, err := rand.Read(bytes); err != nil {
+		return "", fmt.Errorf("failed to generate push token: %w", err)
+	}
+	return hex.EncodeToString(bytes), nil
+}
+
 type AddSiteRequest struct {
-	URL string `json:"url"`
+	SiteSettingsRequest
 }
 
 func (h *SiteHandler) AddSite(w http.ResponseWriter, r *http.Request) {
@@ -51,31 +182,173 @@ func (h *SiteHandler) AddSite(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	if req.URL == "" || len(req.URL) < 10 {
+	if req.URL == nil || *req.URL == "" {
 		http.Error(w, "Invalid URL", http.StatusBadRequest)
 		return
 	}
 
-	site := &models.Site{
-		URL:    req.URL,
-		UserID: userID,
+	site := models.NewSite("", userID)
+	if err := req.apply(site); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
 	}
 
 	ctx := context.Background()
+	if !h.checkParentSite(ctx, w, site) || !h.checkNotificationChannels(ctx, w, site) {
+		return
+	}
+
 	if err := h.storage.CreateSite(ctx, site); err != nil {
 		log.Printf("Failed to create site: %v", err)
 		http.Error(w, "Failed to add site", http.StatusInternalServerError)
 		return
 	}
 
+	if len(site.NotificationChannelIDs) > 0 {
+		if err := h.storage.SetSiteNotificationChannels(ctx, site.ID, userID, site.NotificationChannelIDs); err != nil {
+			log.Printf("Failed to set site notification channels: %v", err)
+			http.Error(w, "Failed to set notification channels", http.StatusInternalServerError)
+			return
+		}
+	}
+
 	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(http.StatusCreated)
+	response := map[string]interface{}{
+		"message":          "Site added successfully",
+		"site_id":          site.ID,
+		"url":              site.URL,
+		"monitor_type":     site.MonitorType,
+		"interval_seconds": site.IntervalSeconds,
+	}
+	if site.PushToken != "" {
+		response["push_url"] = "/api/push/" + site.PushToken
+	}
+	json.NewEncoder(w).Encode(response)
+}
+
+// checkParentSite проверяет родительский монитор сайта среди сайтов пользователя.
+// При ошибке отвечает клиенту и возвращает false.
+func (h *SiteHandler) checkParentSite(ctx context.Context, w http.ResponseWriter, site *models.Site) bool {
+	if site.ParentSiteID == 0 {
+		return true
+	}
+
+	sites, err := h.storage.GetUserSites(ctx, site.UserID)
+	if err != nil {
+		log.Printf("Failed to get user sites: %v", err)
+		http.Error(w, "Failed to get sites", http.StatusInternalServerError)
+		return false
+	}
+
+	if err := checker.ValidateParentSite(*site, sites); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return false
+	}
+
+	return true
+}
+
+// checkNotificationChannels проверяет, что каналы уведомлений сайта принадлежат пользователю.
+// При ошибке отвечает клиенту и возвращает false.
+func (h *SiteHandler) checkNotificationChannels(ctx context.Context, w http.ResponseWriter, site *models.Site) bool {
+	if len(site.NotificationChannelIDs) == 0 {
+		return true
+	}
+
+	channels, err := h.storage.GetUserNotificationChannels(ctx, site.UserID)
+	if err != nil {
+		log.Printf("Failed to get notification channels: %v", err)
+		http.Error(w, "Failed to get notification channels", http.StatusInternalServerError)
+		return false
+	}
+
+	owned := make(map[int]bool, len(channels))
+	for _, channel := range channels {
+		owned[channel.ID] = true
+	}
+	for _, channelID := range site.NotificationChannelIDs {
+		if !owned[channelID] {
+			http.Error(w, fmt.Sprintf("Notification channel %d not found", channelID), http.StatusBadRequest)
+			return false
+		}
+	}
+
+	return true
+}
+
+type UpdateSiteRequest struct {
+	SiteSettingsRequest
+}
+
+// UpdateSite изменяет настройки сайта (URL, интервал и параметры проверки)
+func (h *SiteHandler) UpdateSite(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPut {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	userID, ok := r.Context().Value(middleware.UserIDKey).(int)
+	if !ok {
+		http.Error(w, "User not authenticated", http.StatusUnauthorized)
+		return
+	}
+
+	siteID, err := getSiteIDFromRequest(r)
+	if err != nil {
+		http.Error(w, "Invalid site ID", http.StatusBadRequest)
+		return
+	}
+
+	var req UpdateSiteRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid JSON", http.StatusBadRequest)
+		return
+	}
+
+	ctx := context.Background()
+	site, err := h.storage.GetSiteByID(ctx, siteID)
+	if err != nil {
+		log.Printf("Failed to get site: %v", err)
+		http.Error(w, "Failed to get site", http.StatusInternalServerError)
+		return
+	}
+
+	if site == nil || site.UserID != userID {
+		http.Error(w, "Site not found", http.StatusNotFound)
+		return
+	}
+
+	if err := req.apply(site); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	if !h.checkParentSite(ctx, w, site) || !h.checkNotificationChannels(ctx, w, site) {
+		return
+	}
+
+	if err := h.storage.UpdateSite(ctx, site); err != nil {
+		log.Printf("Failed to update site: %v", err)
+		http.Error(w, "Failed to update site", http.StatusInternalServerError)
+		return
+	}
+
+	if req.NotificationChannelIDs != nil {
+		if err := h.storage.SetSiteNotificationChannels(ctx, site.ID, userID, site.NotificationChannelIDs); err != nil {
+			log.Printf("Failed to set site notification channels: %v", err)
+			http.Error(w, "Failed to set notification channels", http.StatusInternalServerError)
+			return
+		}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
 	json.NewEncoder(w).Encode(map[string]interface{}{
-		"message": "Site added successfully",
-		"site_id": site.ID,
-		"url":     site.URL,
+		"message": "Site updated successfully",
+		"site":    site,
 	})
 }
+
 func (h *SiteHandler) GetSites(w http.ResponseWriter, r *http.Request) {
 	if r.Method != http.MethodGet {
 		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
@@ -149,7 +422,8 @@ func getSiteIDFromRequest(r *http.Request) (int, error) {
 }
 
 type BulkAddSitesRequest struct {
-	URLs []string `json:"urls"`
+	URLs            []string `json:"urls"`
+	IntervalSeconds int      `json:"interval_seconds,omitempty"`
 }
 
 func (h *SiteHandler) BulkAddSites(w http.ResponseWriter, r *http.Request) {
@@ -180,6 +454,15 @@ func (h *SiteHandler) BulkAddSites(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if req.IntervalSeconds == 0 {
+		req.IntervalSeconds = models.DefaultCheckInterval
+	}
+
+	if err := utils.ValidateCheckInterval(req.IntervalSeconds); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
 	var results []map[string]interface{}
 	ctx := context.Background()
 
@@ -188,20 +471,18 @@ func (h *SiteHandler) BulkAddSites(w http.ResponseWriter, r *http.Request) {
 			continue
 		}
 
-		if len(url) < 10 {
+		site := models.NewSite(url, userID)
+		site.IntervalSeconds = req.IntervalSeconds
+
+		if err := checker.ValidateSite(site); err != nil {
 			results = append(results, map[string]interface{}{
 				"url":     url,
 				"status":  "error",
-				"message": "Invalid URL",
+				"message": err.Error(),
 			})
 			continue
 		}
 
-		site := &models.Site{
-			URL:    url,
-			UserID: userID,
-		}
-
 		if err := h.storage.CreateSite(ctx, site); err != nil {
 			results = append(results, map[string]interface{}{
 				"url":     url,
@@ -351,46 +632,15 @@ func (h *SiteHandler) RefreshSites(w http.ResponseWriter, r *http.Request) {
 			siteCtx, cancel := context.WithTimeout(context.Background(), 12*time.Second)
 			defer cancel()
 
-			// Используем статический метод CheckSite для проверки
-			status, err := checker.CheckSite(siteCtx, s.URL)
-			if err != nil {
-				if siteCtx.Err() == context.DeadlineExceeded {
-					log.Printf("Timeout checking site %s (12s)", s.URL)
-				} else {
-					log.Printf("Failed to check site %s: %v", s.URL, err)
-				}
-				status = "DOWN"
-			}
-
-			// Получаем старый статус для сравнения
-			oldSite, err := h.storage.GetSiteByID(ctx, s.ID)
+			// Проверяем через checker, чтобы не запустить проверку сайта, который уже проверяется
+			result, err := h.checker.CheckNow(siteCtx, s)
 			if err != nil {
-				log.Printf("Failed to get old site status for %s: %v", s.URL, err)
+				log.Printf("Skipping refresh for site %s: %v", s.URL, err)
+				return
 			}
 
-			// Обновляем статус в базе данных (используем оригинальный контекст для БД)
-			if err := h.storage.UpdateSiteStatus(ctx, s.ID, status); err != nil {
-				log.Printf("Failed to update site %s status: %v", s.URL, err)
-			} else {
-				log.Printf("Completed check for site %s: %s", s.URL, status)
-				atomic.AddInt64(&updatedCount, 1)
-
-				// Отправляем уведомление, если статус изменился
-				if oldSite != nil && oldSite.LastStatus != status {
-					log.Printf("Status changed for site %s: %s -> %s, sending notification", s.URL, oldSite.LastStatus, status)
-					if h.notifier != nil {
-						if err := h.notifier.NotifySiteStatusChange(ctx, s.ID, oldSite.LastStatus, status); err != nil {
-							log.Printf("Failed to send notification for site %s: %v", s.URL, err)
-						} else {
-							log.Printf("Notification sent for site %s status change", s.URL)
-						}
-					} else {
-						log.Printf("Notifier is nil, skipping notification for site %s", s.URL)
-					}
-				} else if oldSite != nil {
-					log.Printf("Status unchanged for site %s: %s", s.URL, status)
-				}
-			}
+			log.Printf("Completed check for site %s: %s", s.URL, result.Status)
+			atomic.AddInt64(&updatedCount, 1)
 		}(site)
 	}
 
@@ -406,3 +656,118 @@ func (h *SiteHandler) RefreshSites(w http.ResponseWriter, r *http.Request) {
 		"updated": int(updatedCount),
 	})
 }
+
+// GetSiteChecks возвращает историю проверок сайта
+func (h *SiteHandler) GetSiteChecks(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	userID, ok := r.Context().Value(middleware.UserIDKey).(int)
+	if !ok {
+		http.Error(w, "User not authenticated", http.StatusUnauthorized)
+		return
+	}
+
+	siteID, err := getSiteIDFromRequest(r)
+	if err != nil {
+		http.Error(w, "Invalid site ID", http.StatusBadRequest)
+		return
+	}
+
+	limit := 100
+	if limitStr := r.URL.Query().Get("limit"); limitStr != "" {
+		if _, err := fmt.Sscanf(limitStr, "%d", &limit); err != nil || limit <= 0 || limit > 1000 {
+			http.Error(w, "Invalid limit", http.StatusBadRequest)
+			return
+		}
+	}
+
+	since := time.Now().Add(-24 * time.Hour)
+	if sinceStr := r.URL.Query().Get("since"); sinceStr != "" {
+		since, err = time.Parse(time.RFC3339, sinceStr)
+		if err != nil {
+			http.Error(w, "Invalid since, expected RFC3339", http.StatusBadRequest)
+			return
+		}
+	}
+
+	ctx := context.Background()
+	site, err := h.storage.GetSiteByID(ctx, siteID)
+	if err != nil {
+		log.Printf("Failed to get site: %v", err)
+		http.Error(w, "Failed to get site", http.StatusInternalServerError)
+		return
+	}
+
+	if site == nil || site.UserID != userID {
+		http.Error(w, "Site not found", http.StatusNotFound)
+		return
+	}
+
+	results, err := h.storage.GetSiteCheckResults(ctx, siteID, since, limit)
+	if err != nil {
+		log.Printf("Failed to get check results: %v", err)
+		http.Error(w, "Failed to get check results", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"site_id": siteID,
+		"checks":  results,
+		"count":   len(results),
+	})
+}
+
+// GetSiteCertificate возвращает TLS сертификат сайта из последней HTTPS проверки
+func (h *SiteHandler) GetSiteCertificate(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	userID, ok := r.Context().Value(middleware.UserIDKey).(int)
+	if !ok {
+		http.Error(w, "User not authenticated", http.StatusUnauthorized)
+		return
+	}
+
+	siteID, err := getSiteIDFromRequest(r)
+	if err != nil {
+		http.Error(w, "Invalid site ID", http.StatusBadRequest)
+		return
+	}
+
+	ctx := context.Background()
+	site, err := h.storage.GetSiteByID(ctx, siteID)
+	if err != nil {
+		log.Printf("Failed to get site: %v", err)
+		http.Error(w, "Failed to get site", http.StatusInternalServerError)
+		return
+	}
+
+	if site == nil || site.UserID != userID {
+		http.Error(w, "Site not found", http.StatusNotFound)
+		return
+	}
+
+	cert, err := h.storage.GetSiteCertificate(ctx, siteID)
+	if err != nil {
+		log.Printf("Failed to get site certificate: %v", err)
+		http.Error(w, "Failed to get certificate", http.StatusInternalServerError)
+		return
+	}
+
+	if cert == nil {
+		http.Error(w, "Certificate not found", http.StatusNotFound)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"certificate": cert,
+		"days_left":   int(time.Until(cert.NotAfter).Hours() / 24),
+	})
+}
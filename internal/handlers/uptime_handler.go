@@ -0,0 +1,198 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/aouxes/uptime-monitor/internal/middleware"
+	"github.com/aouxes/uptime-monitor/internal/models"
+	"github.com/aouxes/uptime-monitor/internal/stats"
+)
+
+// GetSiteUptime возвращает статистику доступности сайта за период
+func (h *SiteHandler) GetSiteUptime(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	userID, ok := r.Context().Value(middleware.UserIDKey).(int)
+	if !ok {
+		http.Error(w, "User not authenticated", http.StatusUnauthorized)
+		return
+	}
+
+	siteID, err := getSiteIDFromRequest(r)
+	if err != nil {
+		http.Error(w, "Invalid site ID", http.StatusBadRequest)
+		return
+	}
+
+	window := r.URL.Query().Get("window")
+	duration, err := stats.ParseWindow(window)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	ctx := context.Background()
+	site, err := h.storage.GetSiteByID(ctx, siteID)
+	if err != nil {
+		log.Printf("Failed to get site: %v", err)
+		http.Error(w, "Failed to get site", http.StatusInternalServerError)
+		return
+	}
+
+	if site == nil || site.UserID != userID {
+		http.Error(w, "Site not found", http.StatusNotFound)
+		return
+	}
+
+	to := time.Now()
+	from := to.Add(-duration)
+
+	points, err := h.storage.GetStatusPoints(ctx, []int{siteID}, from)
+	if err != nil {
+		log.Printf("Failed to get status points: %v", err)
+		http.Error(w, "Failed to calculate uptime", http.StatusInternalServerError)
+		return
+	}
+
+	report := stats.CalculateUptime(points[siteID], from, to)
+	report.SiteID = site.ID
+	report.URL = site.URL
+	report.Window = windowName(window)
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(report)
+}
+
+// GetUptimeSummary возвращает статистику доступности по всем сайтам пользователя
+func (h *SiteHandler) GetUptimeSummary(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	userID, ok := r.Context().Value(middleware.UserIDKey).(int)
+	if !ok {
+		http.Error(w, "User not authenticated", http.StatusUnauthorized)
+		return
+	}
+
+	window := r.URL.Query().Get("window")
+	duration, err := stats.ParseWindow(window)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	ctx := context.Background()
+	sites, err := h.storage.GetUserSites(ctx, userID)
+	if err != nil {
+		log.Printf("Failed to get user sites: %v", err)
+		http.Error(w, "Failed to get sites", http.StatusInternalServerError)
+		return
+	}
+
+	to := time.Now()
+	from := to.Add(-duration)
+
+	siteIDs := make([]int, 0, len(sites))
+	for _, site := range sites {
+		siteIDs = append(siteIDs, site.ID)
+	}
+
+	points, err := h.storage.GetStatusPoints(ctx, siteIDs, from)
+	if err != nil {
+		log.Printf("Failed to get status points: %v", err)
+		http.Error(w, "Failed to calculate uptime", http.StatusInternalServerError)
+		return
+	}
+
+	reports := make([]models.UptimeReport, 0, len(sites))
+	for _, site := range sites {
+		report := stats.CalculateUptime(points[site.ID], from, to)
+		report.SiteID = site.ID
+		report.URL = site.URL
+		report.Window = windowName(window)
+		reports = append(reports, report)
+	}
+
+	summary := stats.Summarize(reports, from, to)
+	summary.Window = windowName(window)
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"summary": summary,
+		"sites":   reports,
+		"count":   len(reports),
+	})
+}
+
+func windowName(window string) string {
+	if window == "" {
+		return "24h"
+	}
+	return window
+}
+
+// GetSiteLatency возвращает ряд времени ответа сайта с разбивкой по фазам
+func (h *SiteHandler) GetSiteLatency(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	userID, ok := r.Context().Value(middleware.UserIDKey).(int)
+	if !ok {
+		http.Error(w, "User not authenticated", http.StatusUnauthorized)
+		return
+	}
+
+	siteID, err := getSiteIDFromRequest(r)
+	if err != nil {
+		http.Error(w, "Invalid site ID", http.StatusBadRequest)
+		return
+	}
+
+	window := windowName(r.URL.Query().Get("window"))
+	duration, err := stats.ParseWindow(window)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	ctx := context.Background()
+	site, err := h.storage.GetSiteByID(ctx, siteID)
+	if err != nil {
+		log.Printf("Failed to get site: %v", err)
+		http.Error(w, "Failed to get site", http.StatusInternalServerError)
+		return
+	}
+
+	if site == nil || site.UserID != userID {
+		http.Error(w, "Site not found", http.StatusNotFound)
+		return
+	}
+
+	bucket := stats.LatencyBucket(window)
+	points, err := h.storage.GetLatencySeries(ctx, siteID, time.Now().Add(-duration), bucket)
+	if err != nil {
+		log.Printf("Failed to get latency series: %v", err)
+		http.Error(w, "Failed to get latency", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"site_id":        siteID,
+		"window":         window,
+		"bucket_seconds": int64(bucket.Seconds()),
+		"points":         points,
+		"count":          len(points),
+	})
+}
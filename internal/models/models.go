@@ -1,6 +1,7 @@
 package models
 
 import (
+	"encoding/json"
 	"time"
 )
 
@@ -13,11 +14,443 @@ type User struct {
 	CreatedAt      time.Time `json:"created_at"`
 }
 
+// Интервалы проверки сайта по умолчанию и допустимые границы
+const (
+	DefaultCheckInterval = 300
+	MinCheckInterval     = 30
+	MaxCheckInterval     = 86400
+)
+
+// Настройки HTTP проверки по умолчанию
+const (
+	DefaultHTTPMethod          = "HEAD"
+	DefaultExpectedStatusCodes = "200-399"
+	DefaultMaxRedirects        = 10
+)
+
+// Статусы сайта и результата проверки
+const (
+	StatusUp          = "UP"
+	StatusDown        = "DOWN"
+	StatusUnknown     = "UNKNOWN"
+	StatusMaintenance = "MAINTENANCE" // идет окно обслуживания
+	StatusUnreachable = "UNREACHABLE" // недоступен родительский монитор
+)
+
+// Типы мониторов
+const (
+	MonitorHTTP = "http"
+	MonitorTCP  = "tcp"
+	MonitorDNS  = "dns"
+	MonitorPush = "push"
+)
+
+// Пороги подтверждения смены статуса
+const (
+	DefaultFailThreshold    = 1
+	DefaultRecoverThreshold = 1
+	MaxStatusThreshold      = 10
+	MinRetryInterval        = 5
+	MaxRetryInterval        = 3600
+)
+
+// DefaultPushGraceSeconds — запас времени для push монитора сверх интервала по умолчанию
+const DefaultPushGraceSeconds = 60
+
+// Типы DNS записей, поддерживаемые DNS монитором
+const (
+	DNSRecordA     = "A"
+	DNSRecordAAAA  = "AAAA"
+	DNSRecordCNAME = "CNAME"
+	DNSRecordMX    = "MX"
+	DNSRecordTXT   = "TXT"
+	DNSRecordNS    = "NS"
+
+	DefaultDNSRecordType = DNSRecordA
+)
+
 type Site struct {
-	ID          int       `json:"id"`
-	URL         string    `json:"url"`
-	UserID      int       `json:"user_id"`
-	LastStatus  string    `json:"last_status"` // "UP", "DOWN", "UNKNOWN"
-	LastChecked time.Time `json:"last_checked"`
-	CreatedAt   time.Time `json:"created_at"`
+	ID              int       `json:"id"`
+	URL             string    `json:"url"` // для TCP монитора — "host:port", для DNS — имя домена, для push — название
+	MonitorType     string    `json:"monitor_type"`
+	UserID          int       `json:"user_id"`
+	LastStatus      string    `json:"last_status"` // "UP", "DOWN", "UNKNOWN", "MAINTENANCE", "UNREACHABLE"
+	LastChecked     time.Time `json:"last_checked"`
+	IntervalSeconds int       `json:"interval_seconds"`
+	CreatedAt       time.Time `json:"created_at"`
+
+	// Настройки HTTP проверки
+	HTTPMethod          string            `json:"http_method"`
+	HTTPHeaders         map[string]string `json:"http_headers"`
+	HTTPBody            string            `json:"http_body"`
+	ExpectedStatusCodes string            `json:"expected_status_codes"` // например "200-299,401"
+	FollowRedirects     bool              `json:"follow_redirects"`
+	MaxRedirects        int               `json:"max_redirects"`
+
+	// Проверка содержимого ответа
+	Keyword     string `json:"keyword,omitempty"`
+	KeywordMode string `json:"keyword_mode,omitempty"` // "contains", "not_contains", "regex"
+
+	JSONAssertions []JSONAssertion `json:"json_assertions"`
+
+	// Настройки TCP проверки: что отправить после подключения и что ожидать в ответе
+	TCPSend   string `json:"tcp_send,omitempty"`
+	TCPExpect string `json:"tcp_expect,omitempty"`
+
+	// Настройки DNS проверки: тип записи, резолвер ("ip" или "ip:port", пусто — системный)
+	// и ожидаемый набор ответов (пусто — достаточно любого ответа)
+	DNSRecordType string   `json:"dns_record_type"`
+	DNSResolver   string   `json:"dns_resolver,omitempty"`
+	DNSExpected   []string `json:"dns_expected"`
+
+	// Push монитор: задача сама пингует POST /api/push/{token} не реже раза в интервал
+	// (плюс PushGraceSeconds), иначе монитор считается DOWN
+	PushToken        string     `json:"push_token,omitempty"`
+	PushGraceSeconds int        `json:"push_grace_seconds"`
+	LastPushAt       *time.Time `json:"last_push_at,omitempty"`
+
+	// Подтверждение смены статуса: DOWN после FailThreshold сбоев подряд,
+	// UP после RecoverThreshold успешных проверок подряд. RetryIntervalSeconds —
+	// интервал повторной проверки после неподтвержденного сбоя (0 — обычный интервал).
+	FailThreshold        int `json:"fail_threshold"`
+	RecoverThreshold     int `json:"recover_threshold"`
+	RetryIntervalSeconds int `json:"retry_interval_seconds"`
+	ConsecutiveFailures  int `json:"consecutive_failures"`
+	ConsecutiveSuccesses int `json:"consecutive_successes"`
+
+	// Родительский монитор (0 — нет). Пока родитель DOWN или UNREACHABLE, сбой сайта
+	// получает статус UNREACHABLE, а уведомление о нем входит в уведомление родителя.
+	ParentSiteID int `json:"parent_site_id,omitempty"`
+
+	// Каналы уведомлений сайта. Пустой список — каналы пользователя по умолчанию
+	// и связанный с аккаунтом Telegram чат.
+	NotificationChannelIDs []int `json:"notification_channel_ids"`
+
+	LastCheck     *CheckResult `json:"last_check,omitempty"`
+	CertExpiresAt *time.Time   `json:"cert_expires_at,omitempty"`
+}
+
+// CheckResult — результат одной проверки сайта
+type CheckResult struct {
+	ID        int64     `json:"id"`
+	SiteID    int       `json:"site_id"`
+	CheckedAt time.Time `json:"checked_at"`
+	Status    string    `json:"status"` // "UP", "DOWN", "MAINTENANCE", "UNREACHABLE"
+	// Статус сайта после проверки с учетом порогов подтверждения, по нему считается доступность
+	ConfirmedStatus string `json:"confirmed_status"`
+	StatusCode      int    `json:"status_code,omitempty"`
+	ResponseTimeMs  int64  `json:"response_time_ms"`
+	DNSMs           int64  `json:"dns_ms"`
+	ConnectMs       int64  `json:"connect_ms"`
+	TLSMs           int64  `json:"tls_ms"`
+	TTFBMs          int64  `json:"ttfb_ms"`
+	Error           string `json:"error,omitempty"`
+
+	AssertionFailures []string `json:"assertion_failures,omitempty"`
+
+	// Сертификат, полученный при HTTPS проверке (не хранится в истории проверок)
+	Certificate *SiteCertificate `json:"-"`
+}
+
+// CertificateInfo — краткие сведения о сертификате из цепочки
+type CertificateInfo struct {
+	Subject  string    `json:"subject"`
+	Issuer   string    `json:"issuer"`
+	NotAfter time.Time `json:"not_after"`
+}
+
+// SiteCertificate — TLS сертификат сайта, полученный при последней HTTPS проверке
+type SiteCertificate struct {
+	SiteID    int               `json:"site_id"`
+	Subject   string            `json:"subject"`
+	Issuer    string            `json:"issuer"`
+	SANs      []string          `json:"sans"`
+	NotBefore time.Time         `json:"not_before"`
+	NotAfter  time.Time         `json:"not_after"`
+	Chain     []CertificateInfo `json:"chain"`
+	CheckedAt time.Time         `json:"checked_at"`
+}
+
+// StatusPoint — смена статуса сайта: первая проверка серии проверок с одинаковым статусом
+type StatusPoint struct {
+	SiteID    int       `json:"site_id"`
+	CheckedAt time.Time `json:"checked_at"`
+	Status    string    `json:"status"`
+	Checks    int       `json:"checks"` // число проверок серии внутри периода отчета
+}
+
+// UptimeReport — статистика доступности за период
+type UptimeReport struct {
+	SiteID           int       `json:"site_id,omitempty"`
+	URL              string    `json:"url,omitempty"`
+	Window           string    `json:"window"`
+	From             time.Time `json:"from"`
+	To               time.Time `json:"to"`
+	Checks           int       `json:"checks"`
+	UptimePercent    float64   `json:"uptime_percent"`
+	MonitoredSeconds int64     `json:"monitored_seconds"`
+	DowntimeSeconds  int64     `json:"downtime_seconds"`
+	Outages          int       `json:"outages"`
+	MTTRSeconds      int64     `json:"mttr_seconds"`
+	MTBFSeconds      int64     `json:"mtbf_seconds"`
+}
+
+// LatencyPoint — агрегированное время ответа за интервал
+type LatencyPoint struct {
+	Time      time.Time `json:"time"`
+	Checks    int       `json:"checks"`
+	AvgMs     int64     `json:"avg_ms"`
+	MaxMs     int64     `json:"max_ms"`
+	DNSMs     int64     `json:"dns_ms"`
+	ConnectMs int64     `json:"connect_ms"`
+	TLSMs     int64     `json:"tls_ms"`
+	TTFBMs    int64     `json:"ttfb_ms"`
+}
+
+// Incident — период недоступности сайта от перехода в DOWN до восстановления
+type Incident struct {
+	ID              int             `json:"id"`
+	SiteID          int             `json:"site_id"`
+	SiteURL         string          `json:"site_url"`
+	StartedAt       time.Time       `json:"started_at"`
+	ResolvedAt      *time.Time      `json:"resolved_at,omitempty"`
+	DurationSeconds int64           `json:"duration_seconds"` // для открытого инцидента — до текущего момента
+	FirstError      string          `json:"first_error"`
+	LastError       string          `json:"last_error"`
+	FailedChecks    int             `json:"failed_checks"`
+	AcknowledgedAt  *time.Time      `json:"acknowledged_at,omitempty"`
+	AcknowledgedBy  string          `json:"acknowledged_by,omitempty"`
+	Events          []IncidentEvent `json:"events,omitempty"`
+	Notes           []IncidentNote  `json:"notes,omitempty"`
+}
+
+// Типы событий инцидента
+const (
+	IncidentEventOpened       = "opened"
+	IncidentEventErrorChanged = "error_changed"
+	IncidentEventAcknowledged = "acknowledged"
+	IncidentEventEscalated    = "escalated"
+	IncidentEventResolved     = "resolved"
+)
+
+// IncidentEvent — событие в хронологии инцидента
+type IncidentEvent struct {
+	ID        int64     `json:"id"`
+	CreatedAt time.Time `json:"created_at"`
+	Type      string    `json:"type"`
+	Message   string    `json:"message,omitempty"`
+}
+
+// IncidentNote — заметка к инциденту
+type IncidentNote struct {
+	ID        int       `json:"id"`
+	Author    string    `json:"author"`
+	Text      string    `json:"text"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// Фильтры статуса инцидента
+const (
+	IncidentStatusOpen     = "open"
+	IncidentStatusResolved = "resolved"
+)
+
+// IncidentFilter — фильтры списка инцидентов пользователя
+type IncidentFilter struct {
+	SiteID int    // 0 — все сайты
+	Status string // "", IncidentStatusOpen или IncidentStatusResolved
+	From   *time.Time
+	To     *time.Time
+	Limit  int
+}
+
+// Типы каналов уведомлений
+const (
+	ChannelTelegram  = "telegram"
+	ChannelEmail     = "email"
+	ChannelWebhook   = "webhook"
+	ChannelSlack     = "slack"
+	ChannelDiscord   = "discord"
+	ChannelPagerDuty = "pagerduty"
+	ChannelOpsgenie  = "opsgenie"
+	ChannelNtfy      = "ntfy"
+	ChannelGotify    = "gotify"
+	ChannelMatrix    = "matrix"
+)
+
+// NotificationChannel — настроенный пользователем канал уведомлений.
+// Config — настройки канала в JSON, их формат зависит от Type.
+type NotificationChannel struct {
+	ID        int             `json:"id"`
+	UserID    int             `json:"user_id"`
+	Type      string          `json:"type"`
+	Name      string          `json:"name"`
+	Config    json.RawMessage `json:"config"`
+	IsDefault bool            `json:"is_default"` // срабатывает для сайтов без явной маршрутизации
+	Enabled   bool            `json:"enabled"`
+	CreatedAt time.Time       `json:"created_at"`
+}
+
+// Статусы уведомления в очереди notification_outbox
+const (
+	NotificationPending = "pending" // ждет доставки или повтора
+	NotificationSent    = "sent"
+	NotificationFailed  = "failed" // попытки доставки исчерпаны
+)
+
+// Notification — уведомление в очереди на доставку.
+// Delivered — каналы, в которые оно уже доставлено: повтор отправляет только в остальные.
+type Notification struct {
+	ID             int64      `json:"id"`
+	SiteID         int        `json:"site_id"`
+	SiteURL        string     `json:"site_url"`
+	EventType      string     `json:"event_type"`
+	OldStatus      string     `json:"old_status,omitempty"`
+	NewStatus      string     `json:"new_status,omitempty"`
+	Reason         string     `json:"reason,omitempty"`
+	IncidentID     int        `json:"incident_id,omitempty"`
+	CertNotAfter   *time.Time `json:"cert_not_after,omitempty"`
+	CertDaysLeft   int        `json:"cert_days_left,omitempty"`
+	EscalationStep int        `json:"escalation_step,omitempty"` // номер шага эскалации, начиная с 1
+	OccurredAt     time.Time  `json:"occurred_at"`
+	Status         string     `json:"status"`
+	Attempts       int        `json:"attempts"`
+	NextAttemptAt  time.Time  `json:"next_attempt_at"`
+	LastError      string     `json:"last_error,omitempty"`
+	Delivered      []string   `json:"delivered"`
+	CreatedAt      time.Time  `json:"created_at"`
+	SentAt         *time.Time `json:"sent_at,omitempty"`
+}
+
+// Типы окон обслуживания
+const (
+	MaintenanceOnce   = "once"   // разовое окно с StartsAt по EndsAt
+	MaintenanceWeekly = "weekly" // по дням недели в StartTime на DurationMinutes
+	MaintenanceCron   = "cron"   // начало по cron выражению, длительность DurationMinutes
+)
+
+// MaxMaintenanceDurationMinutes — максимальная длительность повторяющегося окна обслуживания
+const MaxMaintenanceDurationMinutes = 7 * 24 * 60
+
+// MaintenanceWindow — плановые работы. Пока окно активно, проверки сайтов продолжаются,
+// но статус сохраняется как MAINTENANCE и уведомления не отправляются.
+type MaintenanceWindow struct {
+	ID              int        `json:"id"`
+	UserID          int        `json:"user_id"`
+	Name            string     `json:"name"`
+	Type            string     `json:"type"`
+	StartsAt        *time.Time `json:"starts_at,omitempty"`
+	EndsAt          *time.Time `json:"ends_at,omitempty"`
+	Weekdays        []int      `json:"weekdays,omitempty"`   // 0 — воскресенье ... 6 — суббота
+	StartTime       string     `json:"start_time,omitempty"` // "ЧЧ:ММ" в Timezone
+	Cron            string     `json:"cron,omitempty"`       // "минута час день месяц день_недели" в Timezone
+	DurationMinutes int        `json:"duration_minutes,omitempty"`
+	Timezone        string     `json:"timezone"`
+	SiteIDs         []int      `json:"site_ids"`
+	CreatedAt       time.Time  `json:"created_at"`
+}
+
+// EscalationPolicy — политика эскалации неподтвержденных падений сайтов SiteIDs.
+// Пока инцидент не подтвержден и не закрыт, шаги выполняются по очереди,
+// а с Repeat после последнего шага — снова с первого.
+type EscalationPolicy struct {
+	ID        int              `json:"id"`
+	UserID    int              `json:"user_id"`
+	Name      string           `json:"name"`
+	Steps     []EscalationStep `json:"steps"`
+	Repeat    bool             `json:"repeat"`
+	SiteIDs   []int            `json:"site_ids"`
+	CreatedAt time.Time        `json:"created_at"`
+}
+
+// MaxEscalationSteps — сколько шагов может быть в политике эскалации
+const MaxEscalationSteps = 10
+
+// EscalationStep — шаг эскалации: через DelayMinutes после предыдущего шага
+// (первый — после начала инцидента) уведомление уходит получателям шага
+type EscalationStep struct {
+	DelayMinutes int   `json:"delay_minutes"`
+	NotifyOwner  bool  `json:"notify_owner,omitempty"` // каналы сайта, как при падении
+	ChannelIDs   []int `json:"channel_ids,omitempty"`  // каналы владельца политики
+}
+
+// IncidentEscalation — открытый неподтвержденный инцидент сайта с политикой эскалации
+type IncidentEscalation struct {
+	IncidentID  int
+	SiteID      int
+	StartedAt   time.Time
+	LastError   string
+	Step        int        // сколько шагов уже выполнено
+	EscalatedAt *time.Time // время последнего шага
+	Policy      EscalationPolicy
+}
+
+// PushDeadline возвращает время, до которого push монитор должен получить следующий пинг
+func (s *Site) PushDeadline() time.Time {
+	last := s.CreatedAt
+	if s.LastPushAt != nil {
+		last = *s.LastPushAt
+	}
+	return last.Add(s.CheckInterval() + time.Duration(s.PushGraceSeconds)*time.Second)
+}
+
+// NewSite создает сайт с настройками проверки по умолчанию
+func NewSite(url string, userID int) *Site {
+	return &Site{
+		URL:                 url,
+		MonitorType:         MonitorHTTP,
+		UserID:              userID,
+		IntervalSeconds:     DefaultCheckInterval,
+		HTTPMethod:          DefaultHTTPMethod,
+		HTTPHeaders:         map[string]string{},
+		ExpectedStatusCodes: DefaultExpectedStatusCodes,
+		JSONAssertions:      []JSONAssertion{},
+		DNSRecordType:       DefaultDNSRecordType,
+		DNSExpected:         []string{},
+		PushGraceSeconds:    DefaultPushGraceSeconds,
+		FailThreshold:       DefaultFailThreshold,
+		RecoverThreshold:    DefaultRecoverThreshold,
+		FollowRedirects:     true,
+		MaxRedirects:        DefaultMaxRedirects,
+	}
+}
+
+// Режимы проверки ключевого слова
+const (
+	KeywordContains    = "contains"
+	KeywordNotContains = "not_contains"
+	KeywordRegex       = "regex"
+)
+
+// Операторы JSON проверок
+const (
+	JSONOpExists         = "exists"
+	JSONOpNotExists      = "not_exists"
+	JSONOpEquals         = "equals"
+	JSONOpNotEquals      = "not_equals"
+	JSONOpContains       = "contains"
+	JSONOpGreater        = "gt"
+	JSONOpGreaterOrEqual = "gte"
+	JSONOpLess           = "lt"
+	JSONOpLessOrEqual    = "lte"
+)
+
+// JSONAssertion — проверка значения в JSON ответе
+type JSONAssertion struct {
+	Path  string      `json:"path"` // например "$.status" или "$.checks[0].db"
+	Op    string      `json:"op"`
+	Value interface{} `json:"value,omitempty"`
+}
+
+// HasBodyAssertions сообщает, нужно ли читать тело ответа для проверки сайта
+func (s *Site) HasBodyAssertions() bool {
+	return s.KeywordMode != "" || len(s.JSONAssertions) > 0
+}
+
+// CheckInterval возвращает интервал проверки сайта
+func (s *Site) CheckInterval() time.Duration {
+	if s.IntervalSeconds <= 0 {
+		return DefaultCheckInterval * time.Second
+	}
+	return time.Duration(s.IntervalSeconds) * time.Second
 }
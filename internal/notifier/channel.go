@@ -0,0 +1,279 @@
+package notifier
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"html"
+	"io"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/aouxes/uptime-monitor/internal/models"
+)
+
+// Типы событий уведомлений
+const (
+	EventStatusChange = "status_change"
+	EventCertExpiry   = "cert_expiry"
+	EventEscalation   = "escalation" // падение не подтверждено за время шага политики эскалации
+	EventTest         = "test"       // проверка настроек канала
+
+	// Инцидент закрыт вручную, событие получают только каналы дежурных
+	EventIncidentResolved = "incident_resolved"
+)
+
+// Event — событие, о котором нужно уведомить владельца сайта
+type Event struct {
+	Type       string        `json:"type"`
+	Site       models.Site   `json:"site"`
+	OccurredAt time.Time     `json:"occurred_at"`
+	OldStatus  string        `json:"old_status,omitempty"`
+	NewStatus  string        `json:"new_status,omitempty"`
+	Reason     string        `json:"reason,omitempty"`      // причина сбоя из результата проверки
+	IncidentID int           `json:"incident_id,omitempty"` // инцидент, открытый падением или закрытый восстановлением
+	Dependents []models.Site `json:"dependents,omitempty"`  // сайты, зависящие от этого сайта
+
+	// Уведомление в очереди, из которого создано событие (0 — событие вне очереди)
+	NotificationID int64 `json:"notification_id,omitempty"`
+
+	// Номер шага эскалации, начиная с 1
+	EscalationStep int `json:"escalation_step,omitempty"`
+
+	// Истечение TLS сертификата
+	CertNotAfter *time.Time `json:"cert_not_after,omitempty"`
+	CertDaysLeft int        `json:"cert_days_left,omitempty"`
+}
+
+// NewTestEvent создает событие для проверки настроек канала
+func NewTestEvent() Event {
+	return Event{
+		Type:       EventTest,
+		Site:       models.Site{URL: "https://example.com", MonitorType: models.MonitorHTTP},
+		OccurredAt: time.Now(),
+	}
+}
+
+// Channel — способ доставки уведомлений (Telegram чат, почта, webhook и т.п.)
+type Channel interface {
+	Send(ctx context.Context, event Event) error
+}
+
+// Factory создает канал по JSON настройкам. owner — владелец канала:
+// из его аккаунта берутся значения по умолчанию (например, связанный Telegram чат).
+// Ошибка означает, что настройки некорректны.
+type Factory func(owner models.User, config json.RawMessage) (Channel, error)
+
+// Registry — известные типы каналов уведомлений.
+// Типы регистрируются при инициализации, до начала отправки уведомлений.
+type Registry struct {
+	factories map[string]Factory
+}
+
+func NewRegistry() *Registry {
+	return &Registry{factories: map[string]Factory{}}
+}
+
+// Register добавляет тип канала
+func (r *Registry) Register(channelType string, factory Factory) {
+	r.factories[channelType] = factory
+}
+
+// New создает канал типа channelType
+func (r *Registry) New(channelType string, owner models.User, config json.RawMessage) (Channel, error) {
+	factory, ok := r.factories[channelType]
+	if !ok {
+		return nil, fmt.Errorf("unsupported channel type %q", channelType)
+	}
+
+	if len(config) == 0 || string(config) == "null" {
+		config = json.RawMessage("{}")
+	}
+
+	return factory(owner, config)
+}
+
+// Types возвращает зарегистрированные типы каналов
+func (r *Registry) Types() []string {
+	types := make([]string, 0, len(r.factories))
+	for channelType := range r.factories {
+		types = append(types, channelType)
+	}
+	sort.Strings(types)
+	return types
+}
+
+// eventTitle возвращает заголовок уведомления о событии и цвет для его оформления
+func eventTitle(event Event) (title, color string) {
+	switch event.Type {
+	case EventCertExpiry:
+		return "Сертификат скоро истекает", "#d97706"
+	case EventEscalation:
+		return "Сайт все еще недоступен", "#dc2626"
+	case EventTest:
+		return "Тестовое уведомление", "#2563eb"
+	}
+
+	switch event.NewStatus {
+	case "UP":
+		return "Сайт вернулся в сеть", "#16a34a"
+	case "DOWN":
+		return "Сайт недоступен", "#dc2626"
+	default:
+		return "Статус сайта: " + event.NewStatus, "#6b7280"
+	}
+}
+
+// Важность события для каналов с приоритетом сообщений
+const (
+	levelNormal   = iota // восстановление, тестовое уведомление
+	levelWarning         // истечение сертификата, прочие статусы
+	levelCritical        // падение сайта и эскалация
+)
+
+// eventLevel возвращает важность события
+func eventLevel(event Event) int {
+	switch {
+	case event.Type == EventTest:
+		return levelNormal
+	case event.Type == EventEscalation:
+		return levelCritical
+	case event.Type == EventStatusChange && event.NewStatus == "DOWN":
+		return levelCritical
+	case event.Type == EventStatusChange && event.NewStatus == "UP":
+		return levelNormal
+	}
+	return levelWarning
+}
+
+// eventField — поле уведомления в виде "название: значение"
+type eventField struct {
+	Name  string
+	Value string
+}
+
+// eventFields возвращает подробности события для уведомлений в чатах
+func eventFields(event Event) []eventField {
+	switch event.Type {
+	case EventCertExpiry:
+		return []eventField{
+			{"Действителен до", event.CertNotAfter.UTC().Format("15:04:05 02.01.2006") + " UTC"},
+			{"Осталось дней", strconv.Itoa(event.CertDaysLeft)},
+		}
+	case EventTest:
+		return []eventField{
+			{"Время", event.OccurredAt.UTC().Format("15:04:05 02.01.2006") + " UTC"},
+			{"Результат", "Канал уведомлений настроен правильно"},
+		}
+	}
+
+	fields := []eventField{{"Статус", event.NewStatus}}
+	if event.OldStatus != "" {
+		fields = append(fields, eventField{"Предыдущий статус", event.OldStatus})
+	}
+	fields = append(fields, eventField{"Время", event.OccurredAt.UTC().Format("15:04:05 02.01.2006") + " UTC"})
+	if event.Reason != "" && event.NewStatus != "UP" {
+		fields = append(fields, eventField{"Причина", event.Reason})
+	}
+	if event.IncidentID != 0 {
+		fields = append(fields, eventField{"Инцидент", "#" + strconv.Itoa(event.IncidentID)})
+	}
+	if event.EscalationStep != 0 {
+		fields = append(fields, eventField{"Эскалация", "шаг " + strconv.Itoa(event.EscalationStep)})
+	}
+	if len(event.Dependents) > 0 {
+		var urls []string
+		for i, dependent := range event.Dependents {
+			if i == maxListedDependents {
+				urls = append(urls, fmt.Sprintf("… и еще %d", len(event.Dependents)-maxListedDependents))
+				break
+			}
+			urls = append(urls, dependent.URL)
+		}
+		fields = append(fields, eventField{fmt.Sprintf("Зависимые сайты (%d)", len(event.Dependents)), strings.Join(urls, "\n")})
+	}
+
+	return fields
+}
+
+// eventText возвращает текст уведомления о событии без разметки
+func eventText(event Event) string {
+	title, _ := eventTitle(event)
+	lines := []string{title, event.Site.URL}
+	for _, field := range eventFields(event) {
+		separator := " "
+		if strings.Contains(field.Value, "\n") {
+			separator = "\n"
+		}
+		lines = append(lines, field.Name+":"+separator+field.Value)
+	}
+	return strings.Join(lines, "\n")
+}
+
+// eventHTML возвращает текст уведомления о событии с HTML разметкой
+func eventHTML(event Event) string {
+	title, _ := eventTitle(event)
+	lines := []string{"<b>" + html.EscapeString(title) + "</b>", html.EscapeString(event.Site.URL)}
+	for _, field := range eventFields(event) {
+		value := strings.ReplaceAll(html.EscapeString(field.Value), "\n", "<br>")
+		lines = append(lines, "<b>"+html.EscapeString(field.Name)+":</b> "+value)
+	}
+	return strings.Join(lines, "<br>")
+}
+
+// isHTTPURL сообщает, что адрес сайта — ссылка (у TCP, DNS и push мониторов это не так)
+func isHTTPURL(address string) bool {
+	return strings.HasPrefix(address, "http://") || strings.HasPrefix(address, "https://")
+}
+
+// maxListedDependents — сколько зависимых сайтов перечислять в уведомлении
+const maxListedDependents = 10
+
+// postJSON отправляет payload запросом POST с заголовками headers и проверяет, что ответ — 2xx
+func postJSON(ctx context.Context, client *http.Client, url string, headers map[string]string, payload interface{}) error {
+	return requestJSON(ctx, client, http.MethodPost, url, headers, payload)
+}
+
+// requestJSON отправляет payload запросом method с заголовками headers и проверяет, что ответ — 2xx
+func requestJSON(ctx context.Context, client *http.Client, method, url string, headers map[string]string, payload interface{}) error {
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("failed to marshal payload: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, url, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	for key, value := range headers {
+		req.Header.Set(key, value)
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to send request: %w", err)
+	}
+	defer resp.Body.Close()
+	io.Copy(io.Discard, io.LimitReader(resp.Body, 64*1024))
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("server returned status %d", resp.StatusCode)
+	}
+
+	return nil
+}
+
+// decodeConfig разбирает JSON настройки канала, запрещая неизвестные поля
+func decodeConfig(config json.RawMessage, target interface{}) error {
+	decoder := json.NewDecoder(bytes.NewReader(config))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(target); err != nil {
+		return fmt.Errorf("invalid channel config: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,66 @@
+package notifier
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/aouxes/uptime-monitor/internal/models"
+	"github.com/aouxes/uptime-monitor/internal/telegram"
+)
+
+func TestRegistryNew(t *testing.T) {
+	registry := NewRegistry()
+	registry.Register(models.ChannelTelegram, newTelegramFactory(telegram.NewClient("")))
+
+	linked := models.User{ID: 1, TelegramChatID: 42}
+	unlinked := models.User{ID: 2}
+
+	tests := []struct {
+		name        string
+		channelType string
+		owner       models.User
+		config      string
+		wantChatID  int64
+		wantErr     bool
+	}{
+		{name: "Linked chat", channelType: "telegram", owner: linked, config: `{}`, wantChatID: 42},
+		{name: "Empty config", channelType: "telegram", owner: linked, config: ``, wantChatID: 42},
+		{name: "Null config", channelType: "telegram", owner: linked, config: `null`, wantChatID: 42},
+		{name: "Explicit chat", channelType: "telegram", owner: unlinked, config: `{"chat_id": -100123}`, wantChatID: -100123},
+		{name: "No chat", channelType: "telegram", owner: unlinked, config: `{}`, wantErr: true},
+		{name: "Unknown field", channelType: "telegram", owner: linked, config: `{"chat": 1}`, wantErr: true},
+		{name: "Invalid JSON", channelType: "telegram", owner: linked, config: `{"chat_id": "x"}`, wantErr: true},
+		{name: "Unknown type", channelType: "fax", owner: linked, config: `{}`, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			channel, err := registry.New(tt.channelType, tt.owner, json.RawMessage(tt.config))
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("New() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+
+			tc, ok := channel.(*telegramChannel)
+			if !ok {
+				t.Fatalf("New() = %T, want *telegramChannel", channel)
+			}
+			if tc.chatID != tt.wantChatID {
+				t.Errorf("chatID = %d, want %d", tc.chatID, tt.wantChatID)
+			}
+		})
+	}
+}
+
+func TestRegistryTypes(t *testing.T) {
+	registry := NewRegistry()
+	registry.Register("webhook", nil)
+	registry.Register("email", nil)
+
+	types := registry.Types()
+	if len(types) != 2 || types[0] != "email" || types[1] != "webhook" {
+		t.Errorf("Types() = %v, want [email webhook]", types)
+	}
+}
@@ -0,0 +1,168 @@
+package notifier
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/aouxes/uptime-monitor/internal/models"
+)
+
+// lastJSONBody возвращает тело последнего запроса и проверяет, что это JSON POST
+func lastJSONBody(t *testing.T, requests []capturedRequest) []byte {
+	t.Helper()
+
+	if len(requests) == 0 {
+		t.Fatal("no requests received")
+	}
+	request := requests[len(requests)-1]
+	if request.method != http.MethodPost || request.header.Get("Content-Type") != "application/json" {
+		t.Errorf("unexpected request: %s %s", request.method, request.header.Get("Content-Type"))
+	}
+	return request.body
+}
+
+func TestSlackChannelSend(t *testing.T) {
+	server, requests := requestCapture(t, http.StatusOK)
+
+	channel, err := newSlackFactory()(models.User{}, json.RawMessage(`{"webhook_url": "`+server.URL+`"}`))
+	if err != nil {
+		t.Fatalf("factory error: %v", err)
+	}
+
+	if err := channel.Send(context.Background(), downEvent()); err != nil {
+		t.Fatalf("Send() error = %v", err)
+	}
+
+	var message struct {
+		Text        string `json:"text"`
+		Attachments []struct {
+			Color  string `json:"color"`
+			Blocks []struct {
+				Type string `json:"type"`
+				Text *struct {
+					Type string `json:"type"`
+					Text string `json:"text"`
+				} `json:"text"`
+				Fields []struct {
+					Type string `json:"type"`
+					Text string `json:"text"`
+				} `json:"fields"`
+			} `json:"blocks"`
+		} `json:"attachments"`
+	}
+	body := lastJSONBody(t, *requests)
+	if err := json.Unmarshal(body, &message); err != nil {
+		t.Fatalf("invalid payload: %v", err)
+	}
+
+	if message.Text != "Сайт недоступен: https://example.com" {
+		t.Errorf("text = %q", message.Text)
+	}
+	if len(message.Attachments) != 1 || message.Attachments[0].Color != "#dc2626" {
+		t.Fatalf("attachments = %+v, want one red attachment", message.Attachments)
+	}
+
+	blocks := message.Attachments[0].Blocks
+	if len(blocks) != 2 || blocks[0].Text == nil || blocks[0].Text.Type != "mrkdwn" || !strings.Contains(blocks[0].Text.Text, "*Сайт недоступен*") {
+		t.Fatalf("blocks = %s", body)
+	}
+
+	var fields []string
+	for _, field := range blocks[1].Fields {
+		fields = append(fields, field.Text)
+	}
+	joined := strings.Join(fields, "|")
+	for _, want := range []string{"*Статус*\nDOWN", "*Причина*\nHTTP 503 &lt;Service Unavailable&gt;", "*Инцидент*\n#12", "*Зависимые сайты (1)*\nhttps://api.example.com"} {
+		if !strings.Contains(joined, want) {
+			t.Errorf("fields %q do not contain %q", fields, want)
+		}
+	}
+}
+
+func TestSlackMessageColors(t *testing.T) {
+	up := downEvent()
+	up.OldStatus, up.NewStatus = "DOWN", "UP"
+
+	tests := []struct {
+		name      string
+		event     Event
+		wantColor string
+	}{
+		{name: "Down", event: downEvent(), wantColor: "#dc2626"},
+		{name: "Up", event: up, wantColor: "#16a34a"},
+		{name: "Certificate", event: Event{Type: EventCertExpiry, CertNotAfter: &up.OccurredAt, CertDaysLeft: 3}, wantColor: "#d97706"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if color := newSlackMessage(tt.event).Attachments[0].Color; color != tt.wantColor {
+				t.Errorf("color = %q, want %q", color, tt.wantColor)
+			}
+		})
+	}
+}
+
+func TestDiscordChannelSend(t *testing.T) {
+	server, requests := requestCapture(t, http.StatusNoContent)
+
+	channel, err := newDiscordFactory()(models.User{}, json.RawMessage(`{"webhook_url": "`+server.URL+`"}`))
+	if err != nil {
+		t.Fatalf("factory error: %v", err)
+	}
+
+	if err := channel.Send(context.Background(), downEvent()); err != nil {
+		t.Fatalf("Send() error = %v", err)
+	}
+
+	var message discordMessage
+	body := lastJSONBody(t, *requests)
+	if err := json.Unmarshal(body, &message); err != nil {
+		t.Fatalf("invalid payload: %v", err)
+	}
+
+	if len(message.Embeds) != 1 {
+		t.Fatalf("embeds = %d, want 1", len(message.Embeds))
+	}
+	embed := message.Embeds[0]
+	if embed.Title != "Сайт недоступен" || embed.URL != "https://example.com" || embed.Color != 0xdc2626 ||
+		embed.Timestamp != "2026-03-01T12:30:00Z" {
+		t.Errorf("embed = %+v", embed)
+	}
+
+	fields := map[string]discordField{}
+	for _, field := range embed.Fields {
+		fields[field.Name] = field
+	}
+	if fields["Статус"].Value != "DOWN" || !fields["Статус"].Inline {
+		t.Errorf("status field = %+v", fields["Статус"])
+	}
+	if fields["Причина"].Value != "HTTP 503 <Service Unavailable>" {
+		t.Errorf("reason field = %+v", fields["Причина"])
+	}
+	if fields["Зависимые сайты (1)"].Value != "https://api.example.com" {
+		t.Errorf("dependents field = %+v", fields["Зависимые сайты (1)"])
+	}
+}
+
+func TestChatChannelErrors(t *testing.T) {
+	server, _ := requestCapture(t, http.StatusNotFound)
+
+	for name, factory := range map[string]Factory{"slack": newSlackFactory(), "discord": newDiscordFactory()} {
+		t.Run(name, func(t *testing.T) {
+			if _, err := factory(models.User{}, json.RawMessage(`{}`)); err == nil {
+				t.Error("factory without webhook_url: error = nil")
+			}
+
+			channel, err := factory(models.User{}, json.RawMessage(`{"webhook_url": "`+server.URL+`"}`))
+			if err != nil {
+				t.Fatalf("factory error: %v", err)
+			}
+			if err := channel.Send(context.Background(), downEvent()); err == nil {
+				t.Error("Send() to a missing webhook: error = nil")
+			}
+		})
+	}
+}
@@ -0,0 +1,80 @@
+package notifier
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/aouxes/uptime-monitor/internal/models"
+)
+
+type discordChannel struct {
+	client     *http.Client
+	webhookURL string
+}
+
+func newDiscordFactory() Factory {
+	client := &http.Client{Timeout: webhookTimeout}
+	return func(owner models.User, config json.RawMessage) (Channel, error) {
+		webhookURL, err := decodeChatWebhookConfig(config)
+		if err != nil {
+			return nil, err
+		}
+		return &discordChannel{client: client, webhookURL: webhookURL}, nil
+	}
+}
+
+// Сообщение Discord с одним embed
+type discordMessage struct {
+	Username string         `json:"username"`
+	Embeds   []discordEmbed `json:"embeds"`
+}
+
+type discordEmbed struct {
+	Title       string         `json:"title"`
+	Description string         `json:"description"`
+	URL         string         `json:"url,omitempty"`
+	Color       int            `json:"color"`
+	Fields      []discordField `json:"fields"`
+	Timestamp   string         `json:"timestamp"`
+}
+
+type discordField struct {
+	Name   string `json:"name"`
+	Value  string `json:"value"`
+	Inline bool   `json:"inline"`
+}
+
+// newDiscordMessage собирает сообщение Discord о событии
+func newDiscordMessage(event Event) discordMessage {
+	title, color := eventTitle(event)
+	rgb, _ := strconv.ParseInt(strings.TrimPrefix(color, "#"), 16, 32)
+
+	embed := discordEmbed{
+		Title:       title,
+		Description: event.Site.URL,
+		Color:       int(rgb),
+		Timestamp:   event.OccurredAt.UTC().Format(time.RFC3339),
+	}
+	// Ссылка на сайт — только для HTTP мониторов
+	if isHTTPURL(event.Site.URL) {
+		embed.URL = event.Site.URL
+	}
+
+	for _, field := range eventFields(event) {
+		embed.Fields = append(embed.Fields, discordField{
+			Name:   field.Name,
+			Value:  field.Value,
+			Inline: !strings.Contains(field.Value, "\n") && len(field.Value) <= 40,
+		})
+	}
+
+	return discordMessage{Username: "Uptime Monitor", Embeds: []discordEmbed{embed}}
+}
+
+func (c *discordChannel) Send(ctx context.Context, event Event) error {
+	return postJSON(ctx, c.client, c.webhookURL, nil, newDiscordMessage(event))
+}
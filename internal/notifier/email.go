@@ -0,0 +1,278 @@
+package notifier
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"crypto/tls"
+	"embed"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	htmltemplate "html/template"
+	"mime"
+	"mime/quotedprintable"
+	"net"
+	"net/mail"
+	"net/smtp"
+	"strconv"
+	"strings"
+	texttemplate "text/template"
+	"time"
+
+	"github.com/aouxes/uptime-monitor/internal/config"
+	"github.com/aouxes/uptime-monitor/internal/models"
+)
+
+// smtpTimeout ограничивает отправку одного письма
+const smtpTimeout = 30 * time.Second
+
+//go:embed templates
+var templateFS embed.FS
+
+var (
+	textTemplates = texttemplate.Must(texttemplate.ParseFS(templateFS, "templates/*.txt"))
+	htmlTemplates = htmltemplate.Must(htmltemplate.ParseFS(templateFS, "templates/*.html"))
+)
+
+// EmailConfig — настройки email канала.
+// Без получателей письма уходят на адрес, указанный при регистрации.
+type EmailConfig struct {
+	To []string `json:"to,omitempty"`
+}
+
+type emailChannel struct {
+	mailer *smtpMailer
+	to     []string
+}
+
+// newEmailFactory создает фабрику email каналов, отправляющих письма через mailer
+func newEmailFactory(mailer *smtpMailer) Factory {
+	return func(owner models.User, config json.RawMessage) (Channel, error) {
+		var cfg EmailConfig
+		if err := decodeConfig(config, &cfg); err != nil {
+			return nil, err
+		}
+
+		to := cfg.To
+		if len(to) == 0 && owner.Email != "" {
+			to = []string{owner.Email}
+		}
+		if len(to) == 0 {
+			return nil, fmt.Errorf("at least one recipient is required")
+		}
+
+		recipients := make([]string, 0, len(to))
+		for _, address := range to {
+			parsed, err := mail.ParseAddress(address)
+			if err != nil {
+				return nil, fmt.Errorf("invalid recipient %q: %w", address, err)
+			}
+			recipients = append(recipients, parsed.Address)
+		}
+
+		return &emailChannel{mailer: mailer, to: recipients}, nil
+	}
+}
+
+func (c *emailChannel) Send(ctx context.Context, event Event) error {
+	subject, text, html, err := renderEmail(event)
+	if err != nil {
+		return err
+	}
+
+	message, err := c.mailer.buildMessage(c.to, subject, text, html)
+	if err != nil {
+		return err
+	}
+
+	return c.mailer.send(ctx, c.to, message)
+}
+
+// emailData — данные шаблонов письма
+type emailData struct {
+	Event
+	Title         string
+	Color         string
+	Time          string
+	CertExpiresAt string
+	CertNotAfter  string
+}
+
+// renderEmail возвращает тему, текстовую и HTML версии письма о событии
+func renderEmail(event Event) (subject, text, html string, err error) {
+	data := emailData{
+		Event: event,
+		Time:  event.OccurredAt.UTC().Format("15:04:05 02.01.2006"),
+	}
+
+	data.Title, data.Color = eventTitle(event)
+
+	var name string
+	switch event.Type {
+	case EventStatusChange:
+		name = "status"
+		if event.NewStatus == "UP" {
+			data.Reason = ""
+		}
+		if event.Site.CertExpiresAt != nil {
+			data.CertExpiresAt = event.Site.CertExpiresAt.UTC().Format("02.01.2006")
+		}
+		subject = fmt.Sprintf("[%s] %s", event.NewStatus, event.Site.URL)
+	case EventEscalation:
+		name = "escalation"
+		subject = fmt.Sprintf("[ESCALATION] %s: сайт все еще недоступен", event.Site.URL)
+	case EventCertExpiry:
+		name = "cert_expiry"
+		data.CertNotAfter = event.CertNotAfter.UTC().Format("15:04:05 02.01.2006")
+		subject = fmt.Sprintf("[CERT] %s: сертификат истекает через %d дн.", event.Site.URL, event.CertDaysLeft)
+	case EventTest:
+		name = "test"
+		subject = "[TEST] Uptime Monitor"
+	default:
+		return "", "", "", fmt.Errorf("unsupported event type %q", event.Type)
+	}
+
+	var textBuf, htmlBuf bytes.Buffer
+	if err := textTemplates.ExecuteTemplate(&textBuf, name+".txt", data); err != nil {
+		return "", "", "", fmt.Errorf("failed to render email text: %w", err)
+	}
+	if err := htmlTemplates.ExecuteTemplate(&htmlBuf, name+".html", data); err != nil {
+		return "", "", "", fmt.Errorf("failed to render email html: %w", err)
+	}
+
+	return subject, textBuf.String(), htmlBuf.String(), nil
+}
+
+// smtpMailer отправляет письма через SMTP сервер из настроек
+type smtpMailer struct {
+	config config.SMTPConfig
+	// tlsConfig переопределяет проверку сертификата сервера (в тестах)
+	tlsConfig *tls.Config
+}
+
+func newSMTPMailer(cfg config.SMTPConfig) *smtpMailer {
+	return &smtpMailer{config: cfg}
+}
+
+func (m *smtpMailer) tlsClientConfig() *tls.Config {
+	if m.tlsConfig != nil {
+		return m.tlsConfig
+	}
+	return &tls.Config{ServerName: m.config.Host}
+}
+
+// buildMessage собирает письмо multipart/alternative с текстовой и HTML версиями
+func (m *smtpMailer) buildMessage(to []string, subject, text, html string) ([]byte, error) {
+	boundary, err := randomBoundary()
+	if err != nil {
+		return nil, err
+	}
+
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "From: %s\r\n", m.config.From)
+	fmt.Fprintf(&buf, "To: %s\r\n", strings.Join(to, ", "))
+	fmt.Fprintf(&buf, "Subject: %s\r\n", mime.QEncoding.Encode("utf-8", subject))
+	fmt.Fprintf(&buf, "Date: %s\r\n", time.Now().Format(time.RFC1123Z))
+	fmt.Fprintf(&buf, "MIME-Version: 1.0\r\n")
+	fmt.Fprintf(&buf, "Content-Type: multipart/alternative; boundary=\"%s\"\r\n\r\n", boundary)
+
+	for _, part := range []struct{ contentType, body string }{
+		{"text/plain; charset=utf-8", text},
+		{"text/html; charset=utf-8", html},
+	} {
+		fmt.Fprintf(&buf, "--%s\r\n", boundary)
+		fmt.Fprintf(&buf, "Content-Type: %s\r\nContent-Transfer-Encoding: quoted-printable\r\n\r\n", part.contentType)
+		qp := quotedprintable.NewWriter(&buf)
+		if _, err := qp.Write([]byte(strings.ReplaceAll(part.body, "\n", "\r\n"))); err != nil {
+			return nil, fmt.Errorf("failed to encode email body: %w", err)
+		}
+		if err := qp.Close(); err != nil {
+			return nil, fmt.Errorf("failed to encode email body: %w", err)
+		}
+		buf.WriteString("\r\n")
+	}
+	fmt.Fprintf(&buf, "--%s--\r\n", boundary)
+
+	return buf.Bytes(), nil
+}
+
+func randomBoundary() (string, error) {
+	bytes := make([]byte, 16)
+	if _, err := rand.Read(bytes); err != nil {
+		return "", fmt.Errorf("failed to generate MIME boundary: %w", err)
+	}
+	return hex.EncodeToString(bytes), nil
+}
+
+// send доставляет письмо получателям to
+func (m *smtpMailer) send(ctx context.Context, to []string, message []byte) error {
+	from, err := mail.ParseAddress(m.config.From)
+	if err != nil {
+		return fmt.Errorf("invalid SMTP_FROM: %w", err)
+	}
+
+	addr := net.JoinHostPort(m.config.Host, strconv.Itoa(m.config.Port))
+	dialer := &net.Dialer{Timeout: smtpTimeout}
+
+	var conn net.Conn
+	if m.config.TLSMode == config.SMTPTLS {
+		conn, err = (&tls.Dialer{NetDialer: dialer, Config: m.tlsClientConfig()}).DialContext(ctx, "tcp", addr)
+	} else {
+		conn, err = dialer.DialContext(ctx, "tcp", addr)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to connect to SMTP server: %w", err)
+	}
+
+	deadline := time.Now().Add(smtpTimeout)
+	if ctxDeadline, ok := ctx.Deadline(); ok && ctxDeadline.Before(deadline) {
+		deadline = ctxDeadline
+	}
+	conn.SetDeadline(deadline)
+
+	client, err := smtp.NewClient(conn, m.config.Host)
+	if err != nil {
+		conn.Close()
+		return fmt.Errorf("failed to start SMTP session: %w", err)
+	}
+	defer client.Close()
+
+	if m.config.TLSMode == config.SMTPStartTLS {
+		if ok, _ := client.Extension("STARTTLS"); !ok {
+			return fmt.Errorf("SMTP server does not support STARTTLS")
+		}
+		if err := client.StartTLS(m.tlsClientConfig()); err != nil {
+			return fmt.Errorf("failed to start TLS: %w", err)
+		}
+	}
+
+	if m.config.Username != "" {
+		auth := smtp.PlainAuth("", m.config.Username, m.config.Password, m.config.Host)
+		if err := client.Auth(auth); err != nil {
+			return fmt.Errorf("SMTP authentication failed: %w", err)
+		}
+	}
+
+	if err := client.Mail(from.Address); err != nil {
+		return fmt.Errorf("SMTP server rejected sender: %w", err)
+	}
+	for _, recipient := range to {
+		if err := client.Rcpt(recipient); err != nil {
+			return fmt.Errorf("SMTP server rejected recipient %s: %w", recipient, err)
+		}
+	}
+
+	writer, err := client.Data()
+	if err != nil {
+		return fmt.Errorf("failed to send email: %w", err)
+	}
+	if _, err := writer.Write(message); err != nil {
+		return fmt.Errorf("failed to send email: %w", err)
+	}
+	if err := writer.Close(); err != nil {
+		return fmt.Errorf("failed to send email: %w", err)
+	}
+
+	return client.Quit()
+}
@@ -0,0 +1,395 @@
+package notifier
+
+import (
+	"bufio"
+	"context"
+	"crypto/tls"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"mime"
+	"mime/multipart"
+	"mime/quotedprintable"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/mail"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/aouxes/uptime-monitor/internal/config"
+	"github.com/aouxes/uptime-monitor/internal/models"
+)
+
+// fakeSMTPServer — минимальный SMTP сервер, запоминающий полученные письма
+type fakeSMTPServer struct {
+	listener net.Listener
+	// tlsConfig включает STARTTLS (или TLS с момента подключения при implicitTLS)
+	tlsConfig   *tls.Config
+	implicitTLS bool
+
+	mu       sync.Mutex
+	auth     string
+	from     string
+	to       []string
+	messages []string
+}
+
+func startFakeSMTPServer(t *testing.T, tlsConfig *tls.Config, implicitTLS bool) *fakeSMTPServer {
+	t.Helper()
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("failed to listen: %v", err)
+	}
+	if implicitTLS {
+		listener = tls.NewListener(listener, tlsConfig)
+	}
+
+	server := &fakeSMTPServer{listener: listener, tlsConfig: tlsConfig, implicitTLS: implicitTLS}
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			go server.serve(conn)
+		}
+	}()
+	t.Cleanup(func() { listener.Close() })
+
+	return server
+}
+
+func (s *fakeSMTPServer) port() int {
+	return s.listener.Addr().(*net.TCPAddr).Port
+}
+
+func (s *fakeSMTPServer) serve(conn net.Conn) {
+	defer conn.Close()
+
+	reader := bufio.NewReader(conn)
+	reply := func(line string) { fmt.Fprintf(conn, "%s\r\n", line) }
+	secure := s.implicitTLS
+
+	reply("220 localhost ESMTP")
+	for {
+		line, err := reader.ReadString('\n')
+		if err != nil {
+			return
+		}
+		line = strings.TrimRight(line, "\r\n")
+		command := strings.ToUpper(strings.SplitN(line, " ", 2)[0])
+
+		switch command {
+		case "EHLO":
+			reply("250-localhost")
+			if s.tlsConfig != nil && !secure {
+				reply("250-STARTTLS")
+			}
+			reply("250 AUTH PLAIN")
+		case "STARTTLS":
+			reply("220 Ready to start TLS")
+			tlsConn := tls.Server(conn, s.tlsConfig)
+			if err := tlsConn.Handshake(); err != nil {
+				return
+			}
+			conn, reader, secure = tlsConn, bufio.NewReader(tlsConn), true
+		case "AUTH":
+			s.mu.Lock()
+			s.auth = line
+			s.mu.Unlock()
+			reply("235 Authentication successful")
+		case "MAIL":
+			s.mu.Lock()
+			s.from = line
+			s.mu.Unlock()
+			reply("250 OK")
+		case "RCPT":
+			s.mu.Lock()
+			s.to = append(s.to, line)
+			s.mu.Unlock()
+			reply("250 OK")
+		case "DATA":
+			reply("354 End data with <CR><LF>.<CR><LF>")
+			var data strings.Builder
+			for {
+				dataLine, err := reader.ReadString('\n')
+				if err != nil {
+					return
+				}
+				if dataLine == ".\r\n" {
+					break
+				}
+				data.WriteString(strings.TrimPrefix(dataLine, "."))
+			}
+			s.mu.Lock()
+			s.messages = append(s.messages, data.String())
+			s.mu.Unlock()
+			reply("250 OK")
+		case "QUIT":
+			reply("221 Bye")
+			return
+		default:
+			reply("250 OK")
+		}
+	}
+}
+
+// testTLSConfigs возвращает настройки TLS сервера и доверяющего ему клиента
+func testTLSConfigs(t *testing.T) (server *tls.Config, client *tls.Config) {
+	t.Helper()
+
+	// Сертификат httptest выдан на 127.0.0.1
+	ts := httptest.NewTLSServer(http.NotFoundHandler())
+	t.Cleanup(ts.Close)
+
+	transport := ts.Client().Transport.(*http.Transport)
+	return ts.TLS, &tls.Config{RootCAs: transport.TLSClientConfig.RootCAs, ServerName: "127.0.0.1"}
+}
+
+func downEvent() Event {
+	return Event{
+		Type:       EventStatusChange,
+		Site:       models.Site{ID: 7, URL: "https://example.com"},
+		OccurredAt: time.Date(2026, 3, 1, 12, 30, 0, 0, time.UTC),
+		OldStatus:  "UP",
+		NewStatus:  "DOWN",
+		Reason:     "HTTP 503 <Service Unavailable>",
+		IncidentID: 12,
+		Dependents: []models.Site{{ID: 8, URL: "https://api.example.com"}},
+	}
+}
+
+// readParts разбирает письмо и возвращает тему и тела частей по Content-Type
+func readParts(t *testing.T, raw string) (string, map[string]string) {
+	t.Helper()
+
+	message, err := mail.ReadMessage(strings.NewReader(raw))
+	if err != nil {
+		t.Fatalf("failed to parse message: %v", err)
+	}
+
+	subject, err := new(mime.WordDecoder).DecodeHeader(message.Header.Get("Subject"))
+	if err != nil {
+		t.Fatalf("failed to decode subject: %v", err)
+	}
+
+	mediaType, params, err := mime.ParseMediaType(message.Header.Get("Content-Type"))
+	if err != nil || mediaType != "multipart/alternative" {
+		t.Fatalf("Content-Type = %q, want multipart/alternative", message.Header.Get("Content-Type"))
+	}
+
+	parts := map[string]string{}
+	reader := multipart.NewReader(message.Body, params["boundary"])
+	for {
+		part, err := reader.NextRawPart()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatalf("failed to read part: %v", err)
+		}
+		body, err := io.ReadAll(quotedprintable.NewReader(part))
+		if err != nil {
+			t.Fatalf("failed to decode part: %v", err)
+		}
+		contentType, _, _ := mime.ParseMediaType(part.Header.Get("Content-Type"))
+		parts[contentType] = string(body)
+	}
+
+	return subject, parts
+}
+
+func TestEmailChannelSend(t *testing.T) {
+	serverTLS, clientTLS := testTLSConfigs(t)
+
+	tests := []struct {
+		name        string
+		tlsMode     string
+		serverTLS   *tls.Config
+		implicitTLS bool
+	}{
+		{name: "Plain", tlsMode: config.SMTPNone},
+		{name: "STARTTLS", tlsMode: config.SMTPStartTLS, serverTLS: serverTLS},
+		{name: "Implicit TLS", tlsMode: config.SMTPTLS, serverTLS: serverTLS, implicitTLS: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := startFakeSMTPServer(t, tt.serverTLS, tt.implicitTLS)
+
+			mailer := newSMTPMailer(config.SMTPConfig{
+				Host:     "127.0.0.1",
+				Port:     server.port(),
+				Username: "monitor",
+				Password: "secret",
+				From:     "Uptime Monitor <monitor@example.com>",
+				TLSMode:  tt.tlsMode,
+			})
+			mailer.tlsConfig = clientTLS
+
+			channel, err := newEmailFactory(mailer)(models.User{Email: "owner@example.com"}, json.RawMessage(`{"to": ["oncall@example.com", "Ops <ops@example.com>"]}`))
+			if err != nil {
+				t.Fatalf("factory error: %v", err)
+			}
+
+			if err := channel.Send(context.Background(), downEvent()); err != nil {
+				t.Fatalf("Send() error = %v", err)
+			}
+
+			server.mu.Lock()
+			defer server.mu.Unlock()
+
+			wantAuth := "AUTH PLAIN " + base64.StdEncoding.EncodeToString([]byte("\x00monitor\x00secret"))
+			if server.auth != wantAuth {
+				t.Errorf("auth = %q, want %q", server.auth, wantAuth)
+			}
+			if server.from != "MAIL FROM:<monitor@example.com>" {
+				t.Errorf("from = %q", server.from)
+			}
+			if len(server.to) != 2 || server.to[0] != "RCPT TO:<oncall@example.com>" || server.to[1] != "RCPT TO:<ops@example.com>" {
+				t.Errorf("to = %q", server.to)
+			}
+			if len(server.messages) != 1 {
+				t.Fatalf("got %d messages, want 1", len(server.messages))
+			}
+
+			subject, parts := readParts(t, server.messages[0])
+			if subject != "[DOWN] https://example.com" {
+				t.Errorf("subject = %q", subject)
+			}
+
+			text := parts["text/plain"]
+			for _, want := range []string{"Сайт недоступен", "https://example.com", "12:30:00 01.03.2026", "HTTP 503 <Service Unavailable>", "#12", "https://api.example.com"} {
+				if !strings.Contains(text, want) {
+					t.Errorf("text part does not contain %q:\n%s", want, text)
+				}
+			}
+
+			html := parts["text/html"]
+			for _, want := range []string{"Сайт недоступен", "HTTP 503 &lt;Service Unavailable&gt;", "<li>https://api.example.com</li>"} {
+				if !strings.Contains(html, want) {
+					t.Errorf("html part does not contain %q:\n%s", want, html)
+				}
+			}
+		})
+	}
+}
+
+func TestEmailChannelRequiresSTARTTLS(t *testing.T) {
+	server := startFakeSMTPServer(t, nil, false)
+
+	mailer := newSMTPMailer(config.SMTPConfig{
+		Host:     "127.0.0.1",
+		Port:     server.port(),
+		Username: "monitor",
+		Password: "secret",
+		From:     "monitor@example.com",
+		TLSMode:  config.SMTPStartTLS,
+	})
+	channel := &emailChannel{mailer: mailer, to: []string{"oncall@example.com"}}
+
+	if err := channel.Send(context.Background(), downEvent()); err == nil {
+		t.Fatal("Send() error = nil, want STARTTLS error")
+	}
+
+	server.mu.Lock()
+	defer server.mu.Unlock()
+	if server.auth != "" || len(server.messages) != 0 {
+		t.Errorf("credentials or message sent without TLS: auth=%q, messages=%d", server.auth, len(server.messages))
+	}
+}
+
+func TestEmailFactory(t *testing.T) {
+	factory := newEmailFactory(newSMTPMailer(config.SMTPConfig{}))
+
+	tests := []struct {
+		name    string
+		owner   models.User
+		config  string
+		wantTo  []string
+		wantErr bool
+	}{
+		{name: "Owner email", owner: models.User{Email: "owner@example.com"}, config: `{}`, wantTo: []string{"owner@example.com"}},
+		{name: "Recipients", owner: models.User{Email: "owner@example.com"}, config: `{"to": ["Ops <ops@example.com>"]}`, wantTo: []string{"ops@example.com"}},
+		{name: "No recipients", config: `{}`, wantErr: true},
+		{name: "Invalid recipient", config: `{"to": ["not an email"]}`, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			channel, err := factory(tt.owner, json.RawMessage(tt.config))
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("factory error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+
+			to := channel.(*emailChannel).to
+			if strings.Join(to, ",") != strings.Join(tt.wantTo, ",") {
+				t.Errorf("to = %v, want %v", to, tt.wantTo)
+			}
+		})
+	}
+}
+
+func TestRenderEmail(t *testing.T) {
+	notAfter := time.Date(2026, 4, 1, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name        string
+		event       Event
+		wantSubject string
+		wantText    []string
+		notText     []string
+	}{
+		{
+			name: "Recovery",
+			event: Event{Type: EventStatusChange, Site: models.Site{URL: "https://example.com"}, OldStatus: "DOWN", NewStatus: "UP",
+				Reason: "stale error"},
+			wantSubject: "[UP] https://example.com",
+			wantText:    []string{"Сайт вернулся в сеть", "Статус: UP (был DOWN)"},
+			notText:     []string{"stale error", "Инцидент"},
+		},
+		{
+			name:        "Certificate expiry",
+			event:       Event{Type: EventCertExpiry, Site: models.Site{URL: "https://example.com"}, CertNotAfter: &notAfter, CertDaysLeft: 7},
+			wantSubject: "[CERT] https://example.com: сертификат истекает через 7 дн.",
+			wantText:    []string{"Сертификат скоро истекает", "00:00:00 01.04.2026", "Осталось дней: 7"},
+		},
+		{
+			name: "Escalation",
+			event: Event{Type: EventEscalation, Site: models.Site{URL: "https://example.com"}, NewStatus: "DOWN",
+				Reason: "HTTP 503", IncidentID: 12, EscalationStep: 2},
+			wantSubject: "[ESCALATION] https://example.com: сайт все еще недоступен",
+			wantText:    []string{"Сайт все еще недоступен", "Причина: HTTP 503", "Инцидент: #12", "Эскалация: шаг 2"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			subject, text, html, err := renderEmail(tt.event)
+			if err != nil {
+				t.Fatalf("renderEmail() error = %v", err)
+			}
+			if subject != tt.wantSubject {
+				t.Errorf("subject = %q, want %q", subject, tt.wantSubject)
+			}
+			for _, want := range tt.wantText {
+				if !strings.Contains(text, want) {
+					t.Errorf("text does not contain %q:\n%s", want, text)
+				}
+			}
+			for _, unwanted := range tt.notText {
+				if strings.Contains(text, unwanted) || strings.Contains(html, unwanted) {
+					t.Errorf("email contains %q", unwanted)
+				}
+			}
+		})
+	}
+}
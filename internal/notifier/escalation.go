@@ -0,0 +1,146 @@
+package notifier
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/aouxes/uptime-monitor/internal/models"
+)
+
+// escalationInterval — как часто проверяются сроки шагов эскалации
+const escalationInterval = 30 * time.Second
+
+// maxEscalationDelayMinutes ограничивает паузу перед шагом эскалации
+const maxEscalationDelayMinutes = 24 * 60
+
+// ValidateEscalationPolicy проверяет шаги политики эскалации
+func ValidateEscalationPolicy(policy *models.EscalationPolicy) error {
+	if len(policy.Steps) == 0 {
+		return fmt.Errorf("at least one escalation step is required")
+	}
+	if len(policy.Steps) > models.MaxEscalationSteps {
+		return fmt.Errorf("too many escalation steps (max %d)", models.MaxEscalationSteps)
+	}
+
+	for i := range policy.Steps {
+		step := &policy.Steps[i]
+		if step.DelayMinutes < 1 || step.DelayMinutes > maxEscalationDelayMinutes {
+			return fmt.Errorf("step %d: delay_minutes must be between 1 and %d", i+1, maxEscalationDelayMinutes)
+		}
+
+		if !step.NotifyOwner && len(step.ChannelIDs) == 0 {
+			return fmt.Errorf("step %d: notify_owner or channel_ids is required", i+1)
+		}
+	}
+
+	return nil
+}
+
+// nextEscalation возвращает индекс следующего шага эскалации инцидента и время, когда его выполнить.
+// Пауза шага отсчитывается от предыдущего шага, для первого — от начала инцидента.
+// ok = false, если все шаги выполнены и повтор не задан.
+func nextEscalation(escalation models.IncidentEscalation) (step int, at time.Time, ok bool) {
+	steps := escalation.Policy.Steps
+	if len(steps) == 0 || (escalation.Step >= len(steps) && !escalation.Policy.Repeat) {
+		return 0, time.Time{}, false
+	}
+
+	base := escalation.StartedAt
+	if escalation.Step > 0 && escalation.EscalatedAt != nil {
+		base = *escalation.EscalatedAt
+	}
+
+	step = escalation.Step % len(steps)
+	return step, base.Add(time.Duration(steps[step].DelayMinutes) * time.Minute), true
+}
+
+// runEscalator выполняет шаги эскалации неподтвержденных падений до отмены ctx
+func (n *Notifier) runEscalator(ctx context.Context) {
+	ticker := time.NewTicker(escalationInterval)
+	defer ticker.Stop()
+
+	for {
+		n.escalate(ctx, time.Now())
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// escalate ставит в очередь уведомления шагов эскалации, срок которых наступил
+func (n *Notifier) escalate(ctx context.Context, now time.Time) {
+	escalations, err := n.storage.GetPendingEscalations(ctx)
+	if err != nil {
+		log.Printf("Failed to get pending escalations: %v", err)
+		return
+	}
+
+	for _, escalation := range escalations {
+		step, at, ok := nextEscalation(escalation)
+		if !ok || now.Before(at) {
+			continue
+		}
+
+		notification := &models.Notification{
+			EventType:      EventEscalation,
+			NewStatus:      "DOWN",
+			Reason:         escalation.LastError,
+			IncidentID:     escalation.IncidentID,
+			EscalationStep: escalation.Step + 1,
+		}
+		message := fmt.Sprintf("Escalation step %d of policy %q", step+1, escalation.Policy.Name)
+
+		escalated, err := n.storage.EscalateIncident(ctx, escalation.IncidentID, escalation.Step, message, notification)
+		if err != nil {
+			log.Printf("Failed to escalate incident %d: %v", escalation.IncidentID, err)
+			continue
+		}
+
+		if escalated {
+			log.Printf("Incident %d escalated: step %d of policy %d", escalation.IncidentID, step+1, escalation.Policy.ID)
+		}
+	}
+}
+
+// escalationChannels возвращает каналы шага эскалации, в который попало уведомление notification.
+// Без политики у сайта уведомление уходит в его обычные каналы.
+func (n *Notifier) escalationChannels(ctx context.Context, owner *models.User, site *models.Site, notification *models.Notification) (map[string]Channel, error) {
+	policy, err := n.storage.GetSiteEscalationPolicy(ctx, site.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	if policy == nil || len(policy.Steps) == 0 || notification.EscalationStep < 1 {
+		return n.siteChannels(ctx, owner, site)
+	}
+
+	step := policy.Steps[(notification.EscalationStep-1)%len(policy.Steps)]
+	channels := map[string]Channel{}
+
+	if step.NotifyOwner {
+		owned, err := n.siteChannels(ctx, owner, site)
+		if err != nil {
+			return nil, err
+		}
+		for name, channel := range owned {
+			channels[name] = channel
+		}
+	}
+
+	if len(step.ChannelIDs) > 0 {
+		selected, err := n.siteChannels(ctx, owner, &models.Site{ID: site.ID, UserID: owner.ID, NotificationChannelIDs: step.ChannelIDs})
+		if err != nil {
+			return nil, err
+		}
+		for name, channel := range selected {
+			channels[name] = channel
+		}
+	}
+
+	return channels, nil
+}
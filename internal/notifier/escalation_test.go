@@ -0,0 +1,108 @@
+package notifier
+
+import (
+	"testing"
+	"time"
+
+	"github.com/aouxes/uptime-monitor/internal/models"
+)
+
+func TestValidateEscalationPolicy(t *testing.T) {
+	tooMany := make([]models.EscalationStep, models.MaxEscalationSteps+1)
+	for i := range tooMany {
+		tooMany[i] = models.EscalationStep{DelayMinutes: 5, NotifyOwner: true}
+	}
+
+	tests := []struct {
+		name    string
+		steps   []models.EscalationStep
+		wantErr bool
+	}{
+		{"valid", []models.EscalationStep{
+			{DelayMinutes: 5, NotifyOwner: true},
+			{DelayMinutes: 10, ChannelIDs: []int{3}},
+		}, false},
+		{"no steps", nil, true},
+		{"too many steps", tooMany, true},
+		{"zero delay", []models.EscalationStep{{DelayMinutes: 0, NotifyOwner: true}}, true},
+		{"delay over a day", []models.EscalationStep{{DelayMinutes: 24*60 + 1, NotifyOwner: true}}, true},
+		{"no targets", []models.EscalationStep{{DelayMinutes: 5}}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			policy := &models.EscalationPolicy{Steps: tt.steps}
+			err := ValidateEscalationPolicy(policy)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ValidateEscalationPolicy() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestNextEscalation(t *testing.T) {
+	started := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	escalated := started.Add(20 * time.Minute)
+	steps := []models.EscalationStep{{DelayMinutes: 5}, {DelayMinutes: 15}}
+
+	tests := []struct {
+		name     string
+		step     int
+		repeat   bool
+		wantStep int
+		wantAt   time.Time
+		wantOK   bool
+	}{
+		{"first step counts from incident start", 0, false, 0, started.Add(5 * time.Minute), true},
+		{"next step counts from previous", 1, false, 1, escalated.Add(15 * time.Minute), true},
+		{"chain finished", 2, false, 0, time.Time{}, false},
+		{"repeat starts over", 2, true, 0, escalated.Add(5 * time.Minute), true},
+		{"repeat second round", 3, true, 1, escalated.Add(15 * time.Minute), true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			escalation := models.IncidentEscalation{
+				StartedAt: started,
+				Step:      tt.step,
+				Policy:    models.EscalationPolicy{Steps: steps, Repeat: tt.repeat},
+			}
+			if tt.step > 0 {
+				escalation.EscalatedAt = &escalated
+			}
+
+			step, at, ok := nextEscalation(escalation)
+			if ok != tt.wantOK || step != tt.wantStep || !at.Equal(tt.wantAt) {
+				t.Errorf("nextEscalation() = (%d, %v, %v), want (%d, %v, %v)", step, at, ok, tt.wantStep, tt.wantAt, tt.wantOK)
+			}
+		})
+	}
+}
+
+func TestEscalationEvent(t *testing.T) {
+	event := Event{Type: EventEscalation, Site: models.Site{ID: 7, URL: "https://example.com"}, NewStatus: "DOWN", IncidentID: 12, EscalationStep: 3}
+
+	if title, color := eventTitle(event); title != "Сайт все еще недоступен" || color != "#dc2626" {
+		t.Errorf("eventTitle() = %q, %q", title, color)
+	}
+	if level := eventLevel(event); level != levelCritical {
+		t.Errorf("eventLevel() = %d, want critical", level)
+	}
+	if action := alertAction(event); action != "trigger" {
+		t.Errorf("alertAction() = %q, want trigger", action)
+	}
+
+	var found bool
+	for _, field := range eventFields(event) {
+		if field.Name == "Эскалация" && field.Value == "шаг 3" {
+			found = true
+		}
+	}
+	if !found {
+		t.Errorf("eventFields() has no escalation step: %v", eventFields(event))
+	}
+
+	if payload := newWebhookPayload(event); payload.Event != EventEscalation || payload.Escalation != 3 {
+		t.Errorf("webhook payload = %+v", payload)
+	}
+}
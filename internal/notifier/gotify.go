@@ -0,0 +1,72 @@
+package notifier
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/aouxes/uptime-monitor/internal/models"
+)
+
+// GotifyConfig — настройки Gotify канала
+type GotifyConfig struct {
+	ServerURL string `json:"server_url"` // например https://gotify.example.com
+	AppToken  string `json:"app_token"`  // токен приложения Gotify
+}
+
+type gotifyChannel struct {
+	client    *http.Client
+	serverURL string
+	appToken  string
+}
+
+func newGotifyFactory() Factory {
+	client := &http.Client{Timeout: webhookTimeout}
+	return func(owner models.User, config json.RawMessage) (Channel, error) {
+		var cfg GotifyConfig
+		if err := decodeConfig(config, &cfg); err != nil {
+			return nil, err
+		}
+
+		if err := validateWebhookURL(cfg.ServerURL); err != nil {
+			return nil, fmt.Errorf("server_url: %w", err)
+		}
+		if cfg.AppToken == "" {
+			return nil, fmt.Errorf("app_token is required")
+		}
+
+		return &gotifyChannel{client: client, serverURL: strings.TrimRight(cfg.ServerURL, "/"), appToken: cfg.AppToken}, nil
+	}
+}
+
+// gotifyMessage — сообщение Gotify API
+type gotifyMessage struct {
+	Title    string                 `json:"title"`
+	Message  string                 `json:"message"`
+	Priority int                    `json:"priority"` // 0 … 10, от 8 — звуковое уведомление на Android
+	Extras   map[string]interface{} `json:"extras,omitempty"`
+}
+
+func newGotifyMessage(event Event) gotifyMessage {
+	title, _ := eventTitle(event)
+
+	message := gotifyMessage{
+		Title:    title,
+		Message:  eventText(event),
+		Priority: 4 + 2*eventLevel(event),
+	}
+	if isHTTPURL(event.Site.URL) {
+		message.Extras = map[string]interface{}{
+			"client::notification": map[string]interface{}{"click": map[string]string{"url": event.Site.URL}},
+		}
+	}
+
+	return message
+}
+
+func (c *gotifyChannel) Send(ctx context.Context, event Event) error {
+	headers := map[string]string{"X-Gotify-Key": c.appToken}
+	return postJSON(ctx, c.client, c.serverURL+"/message", headers, newGotifyMessage(event))
+}
@@ -0,0 +1,96 @@
+package notifier
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/aouxes/uptime-monitor/internal/models"
+)
+
+// MatrixConfig — настройки Matrix канала
+type MatrixConfig struct {
+	HomeserverURL string `json:"homeserver_url"` // например https://matrix.example.com
+	AccessToken   string `json:"access_token"`   // токен пользователя-бота, состоящего в комнате
+	RoomID        string `json:"room_id"`        // ID комнаты вида !abc123:example.com
+}
+
+type matrixChannel struct {
+	client        *http.Client
+	homeserverURL string
+	accessToken   string
+	roomID        string
+}
+
+func newMatrixFactory() Factory {
+	client := &http.Client{Timeout: webhookTimeout}
+	return func(owner models.User, config json.RawMessage) (Channel, error) {
+		var cfg MatrixConfig
+		if err := decodeConfig(config, &cfg); err != nil {
+			return nil, err
+		}
+
+		if err := validateWebhookURL(cfg.HomeserverURL); err != nil {
+			return nil, fmt.Errorf("homeserver_url: %w", err)
+		}
+		if cfg.AccessToken == "" {
+			return nil, fmt.Errorf("access_token is required")
+		}
+		if !strings.HasPrefix(cfg.RoomID, "!") || !strings.Contains(cfg.RoomID, ":") {
+			return nil, fmt.Errorf("room_id must be a room ID like !abc123:example.com")
+		}
+
+		return &matrixChannel{
+			client:        client,
+			homeserverURL: strings.TrimRight(cfg.HomeserverURL, "/"),
+			accessToken:   cfg.AccessToken,
+			roomID:        cfg.RoomID,
+		}, nil
+	}
+}
+
+// matrixMessage — событие m.room.message с текстом и HTML версией
+type matrixMessage struct {
+	MsgType       string `json:"msgtype"`
+	Body          string `json:"body"`
+	Format        string `json:"format"`
+	FormattedBody string `json:"formatted_body"`
+}
+
+func (c *matrixChannel) Send(ctx context.Context, event Event) error {
+	txnID, err := matrixTransactionID(event)
+	if err != nil {
+		return err
+	}
+
+	sendURL := fmt.Sprintf("%s/_matrix/client/v3/rooms/%s/send/m.room.message/%s",
+		c.homeserverURL, url.PathEscape(c.roomID), txnID)
+	headers := map[string]string{"Authorization": "Bearer " + c.accessToken}
+
+	return requestJSON(ctx, c.client, http.MethodPut, sendURL, headers, matrixMessage{
+		MsgType:       "m.text",
+		Body:          eventText(event),
+		Format:        "org.matrix.custom.html",
+		FormattedBody: eventHTML(event),
+	})
+}
+
+// matrixTransactionID возвращает ID транзакции для отправки события. У уведомления из очереди
+// он не меняется между попытками, поэтому homeserver не публикует повтор второй раз.
+// Событие вне очереди (тестовое) получает случайный ID.
+func matrixTransactionID(event Event) (string, error) {
+	if event.NotificationID != 0 {
+		return fmt.Sprintf("uptime-monitor-%d", event.NotificationID), nil
+	}
+
+	txnID := make([]byte, 16)
+	if _, err := rand.Read(txnID); err != nil {
+		return "", fmt.Errorf("failed to generate transaction ID: %w", err)
+	}
+	return hex.EncodeToString(txnID), nil
+}
@@ -2,8 +2,13 @@ package notifier
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"log"
+	"slices"
 
+	"github.com/aouxes/uptime-monitor/internal/config"
+	"github.com/aouxes/uptime-monitor/internal/models"
 	"github.com/aouxes/uptime-monitor/internal/storage"
 	"github.com/aouxes/uptime-monitor/internal/telegram"
 )
@@ -11,38 +16,199 @@ import (
 type Notifier struct {
 	telegram *telegram.Client
 	storage  *storage.Storage
+	registry *Registry
 }
 
-func New(telegramToken string, storage *storage.Storage) *Notifier {
+func New(cfg *config.Config, storage *storage.Storage) *Notifier {
+	client := telegram.NewClient(cfg.TelegramToken)
+
+	registry := NewRegistry()
+	registry.Register(models.ChannelTelegram, newTelegramFactory(client))
+	registry.Register(models.ChannelWebhook, newWebhookFactory())
+	registry.Register(models.ChannelSlack, newSlackFactory())
+	registry.Register(models.ChannelDiscord, newDiscordFactory())
+	registry.Register(models.ChannelPagerDuty, newPagerDutyFactory(cfg.PagerDutyAPIURL))
+	registry.Register(models.ChannelOpsgenie, newOpsgenieFactory(cfg.OpsgenieAPIURL))
+	registry.Register(models.ChannelNtfy, newNtfyFactory())
+	registry.Register(models.ChannelGotify, newGotifyFactory())
+	registry.Register(models.ChannelMatrix, newMatrixFactory())
+	if cfg.SMTP.Enabled() {
+		registry.Register(models.ChannelEmail, newEmailFactory(newSMTPMailer(cfg.SMTP)))
+	}
+
 	return &Notifier{
-		telegram: telegram.NewClient(telegramToken),
+		telegram: client,
 		storage:  storage,
+		registry: registry,
+	}
+}
+
+// Registry возвращает типы каналов, которые умеет отправлять notifier
+func (n *Notifier) Registry() *Registry {
+	return n.registry
+}
+
+// StatusChangeNotification возвращает уведомление о смене статуса сайта для очереди
+// или nil, если о такой смене не уведомляют. Тихая смена статуса все же ставится в очередь,
+// если закрывает оповещение о падении: ее получают только каналы дежурных.
+// reason — причина сбоя из результата проверки (может быть пустой),
+// incidentID — инцидент, открытый или закрытый этой сменой статуса (0, если его нет).
+func StatusChangeNotification(oldStatus, newStatus, reason string, incidentID int) *models.Notification {
+	if oldStatus == newStatus {
+		return nil
+	}
+	if silentStatusChange(oldStatus, newStatus) && !(newStatus == "UP" && incidentID != 0) {
+		return nil
+	}
+
+	return &models.Notification{
+		EventType:  EventStatusChange,
+		OldStatus:  oldStatus,
+		NewStatus:  newStatus,
+		Reason:     reason,
+		IncidentID: incidentID,
+	}
+}
+
+// silentStatusChange сообщает, что о смене статуса не нужно уведомлять:
+// начало обслуживания и штатное восстановление после него, а также сбой
+// из-за недоступного родителя и восстановление после него (о них сообщает уведомление родителя)
+func silentStatusChange(oldStatus, newStatus string) bool {
+	for _, status := range []string{"MAINTENANCE", "UNREACHABLE"} {
+		if newStatus == status || (oldStatus == status && newStatus == "UP") {
+			return true
+		}
+	}
+	return false
+}
+
+// IncidentResolvedNotification возвращает для очереди уведомление о ручном закрытии инцидента,
+// которое закрывает оповещение дежурных о падении
+func IncidentResolvedNotification(incidentID int) *models.Notification {
+	return &models.Notification{
+		EventType:  EventIncidentResolved,
+		IncidentID: incidentID,
+	}
+}
+
+// CertExpiryNotification возвращает предупреждение о скором истечении TLS сертификата для очереди
+func CertExpiryNotification(cert *models.SiteCertificate, daysLeft int) *models.Notification {
+	notAfter := cert.NotAfter
+	return &models.Notification{
+		EventType:    EventCertExpiry,
+		CertNotAfter: &notAfter,
+		CertDaysLeft: daysLeft,
 	}
 }
 
-func (n *Notifier) NotifySiteStatusChange(ctx context.Context, siteID int, oldStatus, newStatus string) error {
-	// Получаем информацию о сайте и пользователе
-	site, err := n.storage.GetSiteByID(ctx, siteID)
+// deliver отправляет уведомление из очереди во все каналы сайта, кроме уже доставленных.
+// Ошибка одного канала не мешает отправке в остальные.
+// Возвращает каналы, в которые уведомление доставлено этой попыткой.
+func (n *Notifier) deliver(ctx context.Context, notification *models.Notification) ([]string, error) {
+	site, err := n.storage.GetSiteByID(ctx, notification.SiteID)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
 	if site == nil {
-		log.Printf("Site with ID %d not found", siteID)
-		return nil
+		log.Printf("Site with ID %d not found", notification.SiteID)
+		return nil, nil
 	}
 
-	// Получаем информацию о пользователе
 	user, err := n.storage.GetUserByID(ctx, site.UserID)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
-	if user == nil || user.TelegramChatID == 0 {
-		log.Printf("User %d has no Telegram chat ID configured", site.UserID)
-		return nil
+	if user == nil {
+		log.Printf("User %d not found", site.UserID)
+		return nil, nil
+	}
+
+	event := Event{
+		Type:         notification.EventType,
+		Site:         *site,
+		OccurredAt:   notification.OccurredAt,
+		OldStatus:    notification.OldStatus,
+		NewStatus:    notification.NewStatus,
+		Reason:       notification.Reason,
+		IncidentID:   notification.IncidentID,
+		CertNotAfter: notification.CertNotAfter,
+		CertDaysLeft: notification.CertDaysLeft,
+
+		EscalationStep: notification.EscalationStep,
+		NotificationID: notification.ID,
+	}
+
+	// Уведомления зависимых сайтов входят в уведомление родителя
+	if event.Type == EventStatusChange {
+		event.Dependents, err = n.storage.GetDependentSites(ctx, site.ID)
+		if err != nil {
+			log.Printf("Failed to get dependent sites of %d: %v", site.ID, err)
+		}
+	}
+
+	var channels map[string]Channel
+	if event.Type == EventEscalation {
+		channels, err = n.escalationChannels(ctx, user, site, notification)
+	} else {
+		channels, err = n.siteChannels(ctx, user, site)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if len(channels) == 0 {
+		log.Printf("User %d has no notification channels configured", user.ID)
+		return nil, nil
+	}
+
+	var delivered []string
+	var errs []error
+	for name, channel := range channels {
+		if slices.Contains(notification.Delivered, name) || (pagingOnly(event) && !pagingChannel(channel)) {
+			continue
+		}
+		if err := channel.Send(ctx, event); err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", name, err))
+			continue
+		}
+		delivered = append(delivered, name)
+	}
+
+	return delivered, errors.Join(errs...)
+}
+
+// siteChannels возвращает каналы, в которые уходят уведомления сайта, по именам.
+// Имена не меняются между попытками: по ним очередь помнит, куда уведомление уже доставлено.
+// Если у сайта нет своей маршрутизации, к каналам по умолчанию добавляется
+// связанный с аккаунтом Telegram чат.
+func (n *Notifier) siteChannels(ctx context.Context, user *models.User, site *models.Site) (map[string]Channel, error) {
+	configured, err := n.storage.GetSiteNotificationChannels(ctx, site)
+	if err != nil {
+		return nil, err
+	}
+
+	channels := make(map[string]Channel, len(configured)+1)
+	linked := len(site.NotificationChannelIDs) == 0 && user.TelegramChatID != 0
+	if linked {
+		channels["linked telegram chat"] = &telegramChannel{client: n.telegram, chatID: user.TelegramChatID}
+	}
+
+	for _, c := range configured {
+		channel, err := n.registry.New(c.Type, *user, c.Config)
+		if err != nil {
+			log.Printf("Skipping notification channel %d (%s): %v", c.ID, c.Type, err)
+			continue
+		}
+
+		// Не дублируем сообщение в связанный чат
+		if tc, ok := channel.(*telegramChannel); ok && linked && tc.chatID == user.TelegramChatID {
+			continue
+		}
+
+		channels[fmt.Sprintf("channel %d (%s)", c.ID, c.Type)] = channel
 	}
 
-	// Отправляем уведомление
-	return n.telegram.SendSiteStatusNotification(ctx, user.TelegramChatID, site.URL, oldStatus, newStatus)
+	return channels, nil
 }
@@ -0,0 +1,94 @@
+package notifier
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/aouxes/uptime-monitor/internal/models"
+)
+
+// NtfyConfig — настройки ntfy канала
+type NtfyConfig struct {
+	TopicURL string `json:"topic_url"` // например https://ntfy.sh/my-alerts
+	Token    string `json:"token"`     // токен доступа к защищенному топику
+}
+
+type ntfyChannel struct {
+	client    *http.Client
+	serverURL string
+	topic     string
+	token     string
+}
+
+func newNtfyFactory() Factory {
+	client := &http.Client{Timeout: webhookTimeout}
+	return func(owner models.User, config json.RawMessage) (Channel, error) {
+		var cfg NtfyConfig
+		if err := decodeConfig(config, &cfg); err != nil {
+			return nil, err
+		}
+
+		if err := validateWebhookURL(cfg.TopicURL); err != nil {
+			return nil, fmt.Errorf("topic_url: %w", err)
+		}
+
+		// Сообщение публикуется JSON запросом в корень сервера, топик передается в теле
+		parsed, _ := url.Parse(strings.TrimRight(cfg.TopicURL, "/"))
+		slash := strings.LastIndex(parsed.Path, "/")
+		topic := parsed.Path[slash+1:]
+		if topic == "" {
+			return nil, fmt.Errorf("topic_url must include a topic, e.g. https://ntfy.sh/my-alerts")
+		}
+		parsed.Path = parsed.Path[:slash]
+
+		return &ntfyChannel{client: client, serverURL: parsed.String(), topic: topic, token: cfg.Token}, nil
+	}
+}
+
+// ntfyMessage — сообщение для публикации в ntfy
+type ntfyMessage struct {
+	Topic    string   `json:"topic"`
+	Title    string   `json:"title"`
+	Message  string   `json:"message"`
+	Priority int      `json:"priority"` // 1 (min) … 5 (max)
+	Tags     []string `json:"tags"`
+	Click    string   `json:"click,omitempty"`
+}
+
+// ntfyTags — эмодзи заголовка ntfy по важности события
+var ntfyTags = map[int]string{
+	levelNormal:   "white_check_mark",
+	levelWarning:  "warning",
+	levelCritical: "rotating_light",
+}
+
+func newNtfyMessage(topic string, event Event) ntfyMessage {
+	title, _ := eventTitle(event)
+	level := eventLevel(event)
+
+	message := ntfyMessage{
+		Topic:    topic,
+		Title:    title,
+		Message:  eventText(event),
+		Priority: 3 + level, // default, high, urgent
+		Tags:     []string{ntfyTags[level]},
+	}
+	if isHTTPURL(event.Site.URL) {
+		message.Click = event.Site.URL
+	}
+
+	return message
+}
+
+func (c *ntfyChannel) Send(ctx context.Context, event Event) error {
+	var headers map[string]string
+	if c.token != "" {
+		headers = map[string]string{"Authorization": "Bearer " + c.token}
+	}
+
+	return postJSON(ctx, c.client, c.serverURL+"/", headers, newNtfyMessage(c.topic, event))
+}
@@ -0,0 +1,113 @@
+package notifier
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+
+	"github.com/aouxes/uptime-monitor/internal/models"
+)
+
+// OpsgenieConfig — настройки Opsgenie канала
+type OpsgenieConfig struct {
+	APIKey   string `json:"api_key"`  // ключ интеграции API
+	Priority string `json:"priority"` // P1 (по умолчанию) … P5
+}
+
+type opsgenieChannel struct {
+	client   *http.Client
+	apiURL   string
+	apiKey   string
+	priority string
+}
+
+func newOpsgenieFactory(apiURL string) Factory {
+	client := &http.Client{Timeout: webhookTimeout}
+	return func(owner models.User, config json.RawMessage) (Channel, error) {
+		var cfg OpsgenieConfig
+		if err := decodeConfig(config, &cfg); err != nil {
+			return nil, err
+		}
+
+		if cfg.APIKey == "" {
+			return nil, fmt.Errorf("api_key is required")
+		}
+
+		switch cfg.Priority {
+		case "":
+			cfg.Priority = "P1"
+		case "P1", "P2", "P3", "P4", "P5":
+		default:
+			return nil, fmt.Errorf("priority must be one of P1, P2, P3, P4, P5")
+		}
+
+		return &opsgenieChannel{client: client, apiURL: apiURL, apiKey: cfg.APIKey, priority: cfg.Priority}, nil
+	}
+}
+
+// Запросы Opsgenie Alert API
+type opsgenieCreateAlert struct {
+	Message     string            `json:"message"`
+	Alias       string            `json:"alias"`
+	Description string            `json:"description"`
+	Source      string            `json:"source"`
+	Entity      string            `json:"entity"`
+	Priority    string            `json:"priority"`
+	Details     map[string]string `json:"details"`
+}
+
+type opsgenieCloseAlert struct {
+	Source string `json:"source"`
+	Note   string `json:"note"`
+}
+
+func (c *opsgenieChannel) Send(ctx context.Context, event Event) error {
+	switch alertAction(event) {
+	case "trigger":
+		return c.createAlert(ctx, event)
+	case "resolve":
+		return c.closeAlert(ctx, event)
+	}
+
+	return nil
+}
+
+func (c *opsgenieChannel) headers() map[string]string {
+	return map[string]string{"Authorization": "GenieKey " + c.apiKey}
+}
+
+// createAlert открывает оповещение о событии
+func (c *opsgenieChannel) createAlert(ctx context.Context, event Event) error {
+	// Длина message в Opsgenie ограничена 130 символами
+	message := alertSummary(event)
+	if runes := []rune(message); len(runes) > 130 {
+		message = string(runes[:127]) + "..."
+	}
+
+	return postJSON(ctx, c.client, c.apiURL+"/v2/alerts", c.headers(), opsgenieCreateAlert{
+		Message:     message,
+		Alias:       alertKey(event),
+		Description: alertSummary(event),
+		Source:      "Uptime Monitor",
+		Entity:      event.Site.URL,
+		Priority:    c.priority,
+		Details: map[string]string{
+			"site_id":     strconv.Itoa(event.Site.ID),
+			"old_status":  event.OldStatus,
+			"new_status":  event.NewStatus,
+			"incident_id": strconv.Itoa(event.IncidentID),
+		},
+	})
+}
+
+// closeAlert закрывает оповещение о сайте по его alias
+func (c *opsgenieChannel) closeAlert(ctx context.Context, event Event) error {
+	closeURL := c.apiURL + "/v2/alerts/" + url.PathEscape(alertKey(event)) + "/close?identifierType=alias"
+	return postJSON(ctx, c.client, closeURL, c.headers(), opsgenieCloseAlert{
+		Source: "Uptime Monitor",
+		Note:   resolveNote(event),
+	})
+}
@@ -0,0 +1,144 @@
+package notifier
+
+import (
+	"context"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/aouxes/uptime-monitor/internal/models"
+)
+
+// Доставка уведомлений из очереди notification_outbox
+const (
+	outboxPollInterval    = 5 * time.Second
+	outboxBatchSize       = 20
+	outboxDeliveryTimeout = 2 * time.Minute
+	outboxLease           = 5 * time.Minute // попытка, не завершенная за это время, повторяется
+	outboxMaxAttempts     = 10
+	outboxBackoff         = 30 * time.Second // пауза перед второй попыткой, дальше удваивается
+	outboxMaxBackoff      = time.Hour
+	outboxRetention       = 7 * 24 * time.Hour // сколько хранятся доставленные уведомления
+	outboxCleanupInterval = time.Hour
+)
+
+// Start запускает доставку уведомлений из очереди и эскалацию неподтвержденных падений до отмены ctx
+func (n *Notifier) Start(ctx context.Context) {
+	log.Printf("Starting notification dispatcher")
+	go n.runEscalator(ctx)
+
+	ticker := time.NewTicker(outboxPollInterval)
+	defer ticker.Stop()
+
+	var lastCleanup time.Time
+	for {
+		n.dispatchPending(ctx)
+
+		if time.Since(lastCleanup) >= outboxCleanupInterval {
+			n.cleanup(ctx)
+			lastCleanup = time.Now()
+		}
+
+		select {
+		case <-ctx.Done():
+			log.Printf("Notification dispatcher stopped")
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// dispatchPending доставляет все уведомления, которым пора уйти
+func (n *Notifier) dispatchPending(ctx context.Context) {
+	for ctx.Err() == nil {
+		batch, err := n.storage.ClaimNotifications(ctx, outboxBatchSize, outboxLease)
+		if err != nil {
+			log.Printf("Failed to claim notifications: %v", err)
+			return
+		}
+
+		var wg sync.WaitGroup
+		for i := range batch {
+			wg.Add(1)
+			go func(notification *models.Notification) {
+				defer wg.Done()
+				n.process(ctx, notification)
+			}(&batch[i])
+		}
+		wg.Wait()
+
+		if len(batch) < outboxBatchSize {
+			return
+		}
+	}
+}
+
+// process выполняет одну попытку доставки и сохраняет ее итог
+func (n *Notifier) process(ctx context.Context, notification *models.Notification) {
+	deliveryCtx, cancel := context.WithTimeout(ctx, outboxDeliveryTimeout)
+	delivered, err := n.deliver(deliveryCtx, notification)
+	cancel()
+
+	// При остановке итог не сохраняем: попытка повторится после lease
+	if ctx.Err() != nil {
+		return
+	}
+
+	notification.Delivered = append(notification.Delivered, delivered...)
+	finishAttempt(notification, err, time.Now())
+
+	switch notification.Status {
+	case models.NotificationFailed:
+		log.Printf("Notification %d for site %d failed after %d attempts: %v",
+			notification.ID, notification.SiteID, notification.Attempts, err)
+	case models.NotificationPending:
+		log.Printf("Notification %d for site %d attempt %d failed, retrying at %s: %v",
+			notification.ID, notification.SiteID, notification.Attempts, notification.NextAttemptAt.Format(time.RFC3339), err)
+	}
+
+	if err := n.storage.SaveNotificationAttempt(ctx, notification); err != nil {
+		log.Printf("Failed to save notification %d attempt: %v", notification.ID, err)
+	}
+}
+
+// finishAttempt переводит уведомление по итогу попытки err: доставлено,
+// повтор с экспоненциальной паузой или отказ после outboxMaxAttempts попыток
+func finishAttempt(notification *models.Notification, err error, now time.Time) {
+	if err == nil {
+		notification.Status = models.NotificationSent
+		notification.SentAt = &now
+		notification.LastError = ""
+		return
+	}
+
+	notification.LastError = err.Error()
+	if notification.Attempts >= outboxMaxAttempts {
+		notification.Status = models.NotificationFailed
+		return
+	}
+
+	notification.Status = models.NotificationPending
+	notification.NextAttemptAt = now.Add(retryDelay(notification.Attempts))
+}
+
+// retryDelay возвращает паузу после attempts неудачных попыток
+func retryDelay(attempts int) time.Duration {
+	delay := outboxBackoff
+	for i := 1; i < attempts && delay < outboxMaxBackoff; i++ {
+		delay *= 2
+	}
+	return min(delay, outboxMaxBackoff)
+}
+
+// cleanup удаляет из очереди давно доставленные уведомления
+func (n *Notifier) cleanup(ctx context.Context) {
+	deleted, err := n.storage.DeleteSentNotifications(ctx, time.Now().Add(-outboxRetention))
+	if err != nil {
+		log.Printf("Failed to clean up notification outbox: %v", err)
+		return
+	}
+
+	if deleted > 0 {
+		log.Printf("Deleted %d delivered notifications from outbox", deleted)
+	}
+}
@@ -0,0 +1,102 @@
+package notifier
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/aouxes/uptime-monitor/internal/models"
+)
+
+func TestRetryDelay(t *testing.T) {
+	tests := []struct {
+		attempts int
+		want     time.Duration
+	}{
+		{1, 30 * time.Second},
+		{2, time.Minute},
+		{3, 2 * time.Minute},
+		{7, 32 * time.Minute},
+		{8, time.Hour},
+		{20, time.Hour},
+	}
+
+	for _, tt := range tests {
+		if got := retryDelay(tt.attempts); got != tt.want {
+			t.Errorf("retryDelay(%d) = %v, want %v", tt.attempts, got, tt.want)
+		}
+	}
+}
+
+func TestFinishAttempt(t *testing.T) {
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+
+	t.Run("sent", func(t *testing.T) {
+		notification := &models.Notification{Status: models.NotificationPending, Attempts: 3, LastError: "timeout"}
+		finishAttempt(notification, nil, now)
+
+		if notification.Status != models.NotificationSent || notification.SentAt == nil || !notification.SentAt.Equal(now) {
+			t.Errorf("status = %s, sent_at = %v, want sent at %v", notification.Status, notification.SentAt, now)
+		}
+		if notification.LastError != "" {
+			t.Errorf("last_error = %q, want empty", notification.LastError)
+		}
+	})
+
+	t.Run("retry", func(t *testing.T) {
+		notification := &models.Notification{Status: models.NotificationPending, Attempts: 2}
+		finishAttempt(notification, errors.New("telegram API returned status 502"), now)
+
+		if notification.Status != models.NotificationPending {
+			t.Errorf("status = %s, want pending", notification.Status)
+		}
+		if want := now.Add(time.Minute); !notification.NextAttemptAt.Equal(want) {
+			t.Errorf("next_attempt_at = %v, want %v", notification.NextAttemptAt, want)
+		}
+		if notification.LastError != "telegram API returned status 502" {
+			t.Errorf("last_error = %q", notification.LastError)
+		}
+	})
+
+	t.Run("failed", func(t *testing.T) {
+		notification := &models.Notification{Status: models.NotificationPending, Attempts: outboxMaxAttempts}
+		finishAttempt(notification, errors.New("boom"), now)
+
+		if notification.Status != models.NotificationFailed {
+			t.Errorf("status = %s, want failed", notification.Status)
+		}
+	})
+}
+
+func TestStatusChangeNotification(t *testing.T) {
+	tests := []struct {
+		oldStatus, newStatus string
+		incidentID           int
+		queued               bool
+	}{
+		{"UP", "DOWN", 7, true},
+		{"DOWN", "UP", 7, true},
+		{"UNKNOWN", "UP", 0, true},
+		{"UP", "UP", 0, false},
+		{"UP", "MAINTENANCE", 0, false},
+		{"MAINTENANCE", "UP", 0, false},
+		{"MAINTENANCE", "UP", 7, true}, // восстановление закрыло инцидент, начатый до обслуживания
+		{"MAINTENANCE", "DOWN", 7, true},
+		{"DOWN", "MAINTENANCE", 0, false},
+		{"DOWN", "UNREACHABLE", 0, false},
+		{"UP", "UNREACHABLE", 0, false},
+		{"UNREACHABLE", "UP", 0, false},
+		{"UNREACHABLE", "UP", 7, true},
+	}
+
+	for _, tt := range tests {
+		notification := StatusChangeNotification(tt.oldStatus, tt.newStatus, "timeout", tt.incidentID)
+		if (notification != nil) != tt.queued {
+			t.Errorf("StatusChangeNotification(%s, %s) queued = %v, want %v", tt.oldStatus, tt.newStatus, notification != nil, tt.queued)
+			continue
+		}
+		if notification != nil && (notification.EventType != EventStatusChange || notification.IncidentID != tt.incidentID || notification.Reason != "timeout") {
+			t.Errorf("StatusChangeNotification(%s, %s) = %+v", tt.oldStatus, tt.newStatus, notification)
+		}
+	}
+}
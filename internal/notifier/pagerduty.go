@@ -0,0 +1,192 @@
+package notifier
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/aouxes/uptime-monitor/internal/models"
+)
+
+// alertKey — ключ дедупликации оповещений о сайте: повторные падения
+// обновляют открытое оповещение, а восстановление закрывает его
+func alertKey(event Event) string {
+	if event.Type == EventTest {
+		return "uptime-monitor-test"
+	}
+	return fmt.Sprintf("uptime-monitor-site-%d", event.Site.ID)
+}
+
+// alertAction возвращает, что сделать с оповещением дежурных при событии:
+// открыть при падении или эскалации, закрыть при восстановлении сайта, закрывшем инцидент
+// (в том числе после обслуживания или недоступности родителя), или ручном закрытии инцидента.
+// Пустая строка — ничего.
+// Тестовое оповещение только открывается: закрытие сразу после открытия может обогнать
+// асинхронное создание оповещения, поэтому его закрывают вручную.
+func alertAction(event Event) string {
+	switch event.Type {
+	case EventTest, EventEscalation:
+		return "trigger"
+	case EventIncidentResolved:
+		return "resolve"
+	case EventStatusChange:
+	default:
+		return ""
+	}
+	switch {
+	case event.NewStatus == "DOWN":
+		return "trigger"
+	case event.NewStatus == "UP" && (event.OldStatus == "DOWN" || event.IncidentID != 0):
+		return "resolve"
+	}
+	return ""
+}
+
+// alertSummary — краткое описание падения для оповещения
+func alertSummary(event Event) string {
+	var summary string
+	switch event.Type {
+	case EventTest:
+		return "Uptime Monitor test alert, close it manually"
+	case EventEscalation:
+		summary = fmt.Sprintf("%s is still DOWN, escalation step %d", event.Site.URL, event.EscalationStep)
+	default:
+		summary = fmt.Sprintf("%s is DOWN", event.Site.URL)
+	}
+	if event.Reason != "" {
+		summary += ": " + event.Reason
+	}
+	return summary
+}
+
+// resolveNote — пояснение к закрытию оповещения
+func resolveNote(event Event) string {
+	if event.Type == EventIncidentResolved {
+		return fmt.Sprintf("%s: incident resolved manually", event.Site.URL)
+	}
+	return fmt.Sprintf("%s is back UP", event.Site.URL)
+}
+
+// pagingOnly сообщает, что событие только закрывает оповещение дежурных и в остальные каналы
+// не отправляется: тихое восстановление после обслуживания или недоступности родителя,
+// закрывшее инцидент, и ручное закрытие инцидента
+func pagingOnly(event Event) bool {
+	switch event.Type {
+	case EventIncidentResolved:
+		return true
+	case EventStatusChange:
+		return silentStatusChange(event.OldStatus, event.NewStatus)
+	}
+	return false
+}
+
+// pagingChannel сообщает, что канал поднимает дежурных (PagerDuty, Opsgenie)
+func pagingChannel(channel Channel) bool {
+	switch channel.(type) {
+	case *pagerDutyChannel, *opsgenieChannel:
+		return true
+	}
+	return false
+}
+
+// PagerDutyConfig — настройки PagerDuty канала
+type PagerDutyConfig struct {
+	RoutingKey string `json:"routing_key"` // Integration Key сервиса (Events API v2)
+	Severity   string `json:"severity"`    // critical (по умолчанию), error, warning или info
+}
+
+type pagerDutyChannel struct {
+	client     *http.Client
+	apiURL     string
+	routingKey string
+	severity   string
+}
+
+func newPagerDutyFactory(apiURL string) Factory {
+	client := &http.Client{Timeout: webhookTimeout}
+	return func(owner models.User, config json.RawMessage) (Channel, error) {
+		var cfg PagerDutyConfig
+		if err := decodeConfig(config, &cfg); err != nil {
+			return nil, err
+		}
+
+		if cfg.RoutingKey == "" {
+			return nil, fmt.Errorf("routing_key is required")
+		}
+
+		switch cfg.Severity {
+		case "":
+			cfg.Severity = "critical"
+		case "critical", "error", "warning", "info":
+		default:
+			return nil, fmt.Errorf("severity must be one of critical, error, warning, info")
+		}
+
+		return &pagerDutyChannel{client: client, apiURL: apiURL, routingKey: cfg.RoutingKey, severity: cfg.Severity}, nil
+	}
+}
+
+// Событие PagerDuty Events API v2
+type pagerDutyEvent struct {
+	RoutingKey  string            `json:"routing_key"`
+	EventAction string            `json:"event_action"`
+	DedupKey    string            `json:"dedup_key"`
+	Payload     *pagerDutyPayload `json:"payload,omitempty"` // только для trigger
+	Links       []pagerDutyLink   `json:"links,omitempty"`
+}
+
+type pagerDutyPayload struct {
+	Summary       string                 `json:"summary"`
+	Source        string                 `json:"source"`
+	Severity      string                 `json:"severity"`
+	Timestamp     string                 `json:"timestamp"`
+	Component     string                 `json:"component"`
+	CustomDetails map[string]interface{} `json:"custom_details"`
+}
+
+type pagerDutyLink struct {
+	Href string `json:"href"`
+	Text string `json:"text"`
+}
+
+func (c *pagerDutyChannel) Send(ctx context.Context, event Event) error {
+	action := alertAction(event)
+	if action == "" {
+		return nil
+	}
+
+	return c.enqueue(ctx, action, event)
+}
+
+// enqueue отправляет событие action (trigger или resolve) в Events API v2
+func (c *pagerDutyChannel) enqueue(ctx context.Context, action string, event Event) error {
+	request := pagerDutyEvent{
+		RoutingKey:  c.routingKey,
+		EventAction: action,
+		DedupKey:    alertKey(event),
+	}
+
+	if action == "trigger" {
+		request.Payload = &pagerDutyPayload{
+			Summary:   alertSummary(event),
+			Source:    event.Site.URL,
+			Severity:  c.severity,
+			Timestamp: event.OccurredAt.UTC().Format(time.RFC3339),
+			Component: event.Site.MonitorType,
+			CustomDetails: map[string]interface{}{
+				"site_id":     event.Site.ID,
+				"old_status":  event.OldStatus,
+				"new_status":  event.NewStatus,
+				"error":       event.Reason,
+				"incident_id": event.IncidentID,
+			},
+		}
+		if isHTTPURL(event.Site.URL) {
+			request.Links = []pagerDutyLink{{Href: event.Site.URL, Text: "Site"}}
+		}
+	}
+
+	return postJSON(ctx, c.client, c.apiURL+"/v2/enqueue", nil, request)
+}
@@ -0,0 +1,197 @@
+package notifier
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/aouxes/uptime-monitor/internal/models"
+)
+
+// pagingEvents — падение, восстановление и события, которые не должны поднимать дежурных
+func pagingEvents() []Event {
+	down := downEvent()
+
+	up := downEvent()
+	up.OldStatus, up.NewStatus, up.Reason, up.IncidentID = "DOWN", "UP", "", 0
+
+	firstCheck := up
+	firstCheck.OldStatus = "UNKNOWN"
+
+	notAfter := time.Date(2026, 4, 1, 0, 0, 0, 0, time.UTC)
+	cert := Event{Type: EventCertExpiry, Site: down.Site, CertNotAfter: &notAfter, CertDaysLeft: 7}
+
+	return []Event{down, firstCheck, cert, up}
+}
+
+func TestPagerDutyChannelSend(t *testing.T) {
+	server, requests := requestCapture(t, http.StatusAccepted)
+
+	channel, err := newPagerDutyFactory(server.URL)(models.User{}, json.RawMessage(`{"routing_key": "R0UT1NG"}`))
+	if err != nil {
+		t.Fatalf("factory error: %v", err)
+	}
+
+	for _, event := range pagingEvents() {
+		if err := channel.Send(context.Background(), event); err != nil {
+			t.Fatalf("Send(%s %s->%s) error = %v", event.Type, event.OldStatus, event.NewStatus, err)
+		}
+	}
+
+	if len(*requests) != 2 {
+		t.Fatalf("got %d requests, want trigger and resolve", len(*requests))
+	}
+
+	trigger, resolve := (*requests)[0].fields(t), (*requests)[1].fields(t)
+	for _, request := range *requests {
+		body := request.fields(t)
+		if request.path != "/v2/enqueue" || body["routing_key"] != "R0UT1NG" || body["dedup_key"] != "uptime-monitor-site-7" {
+			t.Errorf("unexpected request: %s %v", request.path, body)
+		}
+	}
+
+	if trigger["event_action"] != "trigger" {
+		t.Errorf("first event_action = %v, want trigger", trigger["event_action"])
+	}
+	payload, _ := trigger["payload"].(map[string]interface{})
+	if payload["summary"] != "https://example.com is DOWN: HTTP 503 <Service Unavailable>" || payload["severity"] != "critical" ||
+		payload["source"] != "https://example.com" || payload["timestamp"] != "2026-03-01T12:30:00Z" {
+		t.Errorf("trigger payload = %v", payload)
+	}
+
+	if resolve["event_action"] != "resolve" || resolve["payload"] != nil {
+		t.Errorf("resolve event = %v", resolve)
+	}
+}
+
+func TestOpsgenieChannelSend(t *testing.T) {
+	server, requests := requestCapture(t, http.StatusAccepted)
+
+	channel, err := newOpsgenieFactory(server.URL)(models.User{}, json.RawMessage(`{"api_key": "k3y", "priority": "P2"}`))
+	if err != nil {
+		t.Fatalf("factory error: %v", err)
+	}
+
+	for _, event := range pagingEvents() {
+		if err := channel.Send(context.Background(), event); err != nil {
+			t.Fatalf("Send(%s %s->%s) error = %v", event.Type, event.OldStatus, event.NewStatus, err)
+		}
+	}
+
+	if len(*requests) != 2 {
+		t.Fatalf("got %d requests, want create and close", len(*requests))
+	}
+
+	create, closeAlert := (*requests)[0], (*requests)[1]
+	for _, request := range *requests {
+		if authorization := request.header.Get("Authorization"); authorization != "GenieKey k3y" {
+			t.Errorf("authorization = %q", authorization)
+		}
+	}
+
+	body := create.fields(t)
+	if create.path != "/v2/alerts" || body["alias"] != "uptime-monitor-site-7" || body["priority"] != "P2" ||
+		body["message"] != "https://example.com is DOWN: HTTP 503 <Service Unavailable>" {
+		t.Errorf("create alert = %s %v", create.path, body)
+	}
+
+	if closeAlert.path != "/v2/alerts/uptime-monitor-site-7/close?identifierType=alias" {
+		t.Errorf("close path = %s", closeAlert.path)
+	}
+}
+
+func TestAlertAction(t *testing.T) {
+	tests := []struct {
+		eventType, oldStatus, newStatus string
+		incidentID                      int
+		want                            string
+	}{
+		{EventStatusChange, "UP", "DOWN", 7, "trigger"},
+		{EventStatusChange, "MAINTENANCE", "DOWN", 0, "trigger"},
+		{EventStatusChange, "DOWN", "UP", 7, "resolve"},
+		{EventStatusChange, "DOWN", "UP", 0, "resolve"},
+		{EventStatusChange, "MAINTENANCE", "UP", 7, "resolve"},
+		{EventStatusChange, "DOWN", "MAINTENANCE", 0, ""},
+		{EventStatusChange, "DOWN", "UNREACHABLE", 0, ""},
+		{EventStatusChange, "UNKNOWN", "UP", 0, ""},
+		{EventStatusChange, "UNREACHABLE", "UP", 0, ""},
+		{EventStatusChange, "UNREACHABLE", "UP", 7, "resolve"},
+		{EventIncidentResolved, "", "", 7, "resolve"},
+		{EventEscalation, "", "DOWN", 7, "trigger"},
+		{EventTest, "", "", 0, "trigger"},
+		{EventCertExpiry, "", "", 0, ""},
+	}
+
+	for _, tt := range tests {
+		event := Event{Type: tt.eventType, OldStatus: tt.oldStatus, NewStatus: tt.newStatus, IncidentID: tt.incidentID}
+		if got := alertAction(event); got != tt.want {
+			t.Errorf("alertAction(%s %s->%s) = %q, want %q", tt.eventType, tt.oldStatus, tt.newStatus, got, tt.want)
+		}
+	}
+}
+
+func TestPagingOnly(t *testing.T) {
+	pagerDuty, _ := newPagerDutyFactory("")(models.User{}, json.RawMessage(`{"routing_key": "key"}`))
+	opsgenie, _ := newOpsgenieFactory("")(models.User{}, json.RawMessage(`{"api_key": "key"}`))
+	webhook, _ := newWebhookFactory()(models.User{}, json.RawMessage(`{"url": "https://example.com/hook"}`))
+	if !pagingChannel(pagerDuty) || !pagingChannel(opsgenie) || pagingChannel(webhook) {
+		t.Errorf("pagingChannel: PagerDuty %v, Opsgenie %v, webhook %v", pagingChannel(pagerDuty), pagingChannel(opsgenie), pagingChannel(webhook))
+	}
+
+	tests := []struct {
+		event Event
+		want  bool
+	}{
+		{Event{Type: EventStatusChange, OldStatus: "MAINTENANCE", NewStatus: "UP", IncidentID: 7}, true},
+		{Event{Type: EventStatusChange, OldStatus: "UNREACHABLE", NewStatus: "UP", IncidentID: 7}, true},
+		{Event{Type: EventStatusChange, OldStatus: "DOWN", NewStatus: "UP"}, false},
+		{Event{Type: EventIncidentResolved}, true},
+		{Event{Type: EventEscalation}, false},
+	}
+
+	for _, tt := range tests {
+		if got := pagingOnly(tt.event); got != tt.want {
+			t.Errorf("pagingOnly(%s %s->%s) = %v, want %v", tt.event.Type, tt.event.OldStatus, tt.event.NewStatus, got, tt.want)
+		}
+	}
+}
+
+func TestAlertSummary(t *testing.T) {
+	escalation := downEvent()
+	escalation.Type, escalation.EscalationStep = EventEscalation, 2
+
+	if got, want := alertSummary(escalation), "https://example.com is still DOWN, escalation step 2: HTTP 503 <Service Unavailable>"; got != want {
+		t.Errorf("alertSummary(escalation) = %q, want %q", got, want)
+	}
+
+	if got, want := resolveNote(Event{Type: EventIncidentResolved, Site: escalation.Site}), "https://example.com: incident resolved manually"; got != want {
+		t.Errorf("resolveNote(manual) = %q, want %q", got, want)
+	}
+}
+
+func TestPagingFactories(t *testing.T) {
+	tests := []struct {
+		name    string
+		factory Factory
+		config  string
+		wantErr bool
+	}{
+		{name: "PagerDuty", factory: newPagerDutyFactory(""), config: `{"routing_key": "key", "severity": "warning"}`},
+		{name: "PagerDuty without key", factory: newPagerDutyFactory(""), config: `{}`, wantErr: true},
+		{name: "PagerDuty invalid severity", factory: newPagerDutyFactory(""), config: `{"routing_key": "key", "severity": "high"}`, wantErr: true},
+		{name: "Opsgenie", factory: newOpsgenieFactory(""), config: `{"api_key": "key"}`},
+		{name: "Opsgenie without key", factory: newOpsgenieFactory(""), config: `{}`, wantErr: true},
+		{name: "Opsgenie invalid priority", factory: newOpsgenieFactory(""), config: `{"api_key": "key", "priority": "P0"}`, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := tt.factory(models.User{}, json.RawMessage(tt.config))
+			if (err != nil) != tt.wantErr {
+				t.Errorf("factory error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
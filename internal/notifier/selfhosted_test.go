@@ -0,0 +1,223 @@
+package notifier
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/aouxes/uptime-monitor/internal/models"
+)
+
+// capturedRequest — запрос, полученный HTTP заглушкой сервера уведомлений
+type capturedRequest struct {
+	method string
+	path   string
+	header http.Header
+	body   []byte
+}
+
+// fields разбирает JSON тело запроса
+func (r capturedRequest) fields(t *testing.T) map[string]interface{} {
+	t.Helper()
+
+	var fields map[string]interface{}
+	if err := json.Unmarshal(r.body, &fields); err != nil {
+		t.Errorf("invalid request body: %s", r.body)
+	}
+	return fields
+}
+
+// requestCapture запускает HTTP заглушку сервера уведомлений, которая запоминает
+// полученные запросы и отвечает на них status
+func requestCapture(t *testing.T, status int) (*httptest.Server, *[]capturedRequest) {
+	t.Helper()
+
+	var requests []capturedRequest
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		requests = append(requests, capturedRequest{method: r.Method, path: r.URL.RequestURI(), header: r.Header, body: body})
+		w.WriteHeader(status)
+		w.Write([]byte(`{}`))
+	}))
+	t.Cleanup(server.Close)
+
+	return server, &requests
+}
+
+func TestNtfyChannelSend(t *testing.T) {
+	server, requests := requestCapture(t, http.StatusOK)
+
+	channel, err := newNtfyFactory()(models.User{}, json.RawMessage(`{"topic_url": "`+server.URL+`/uptime-alerts", "token": "tk_123"}`))
+	if err != nil {
+		t.Fatalf("factory error: %v", err)
+	}
+
+	up := downEvent()
+	up.OldStatus, up.NewStatus = "DOWN", "UP"
+
+	tests := []struct {
+		event        Event
+		wantPriority int
+		wantTag      string
+	}{
+		{event: downEvent(), wantPriority: 5, wantTag: "rotating_light"},
+		{event: up, wantPriority: 3, wantTag: "white_check_mark"},
+	}
+
+	for i, tt := range tests {
+		if err := channel.Send(context.Background(), tt.event); err != nil {
+			t.Fatalf("Send() error = %v", err)
+		}
+
+		request := (*requests)[i]
+		if request.method != http.MethodPost || request.path != "/" || request.header.Get("Authorization") != "Bearer tk_123" {
+			t.Errorf("request = %s %s, Authorization %q", request.method, request.path, request.header.Get("Authorization"))
+		}
+
+		var message ntfyMessage
+		if err := json.Unmarshal(request.body, &message); err != nil {
+			t.Fatalf("invalid payload: %v", err)
+		}
+		if message.Topic != "uptime-alerts" || message.Priority != tt.wantPriority || len(message.Tags) != 1 ||
+			message.Tags[0] != tt.wantTag || message.Click != "https://example.com" {
+			t.Errorf("%s: message = %+v", tt.event.NewStatus, message)
+		}
+	}
+
+	var message ntfyMessage
+	json.Unmarshal((*requests)[0].body, &message)
+	if message.Title != "Сайт недоступен" || !strings.Contains(message.Message, "Причина: HTTP 503 <Service Unavailable>") {
+		t.Errorf("down message = %+v", message)
+	}
+}
+
+func TestGotifyChannelSend(t *testing.T) {
+	server, requests := requestCapture(t, http.StatusOK)
+
+	channel, err := newGotifyFactory()(models.User{}, json.RawMessage(`{"server_url": "`+server.URL+`/", "app_token": "AbC"}`))
+	if err != nil {
+		t.Fatalf("factory error: %v", err)
+	}
+
+	if err := channel.Send(context.Background(), downEvent()); err != nil {
+		t.Fatalf("Send() error = %v", err)
+	}
+
+	request := (*requests)[0]
+	if request.path != "/message" || request.header.Get("X-Gotify-Key") != "AbC" {
+		t.Errorf("request = %s, X-Gotify-Key %q", request.path, request.header.Get("X-Gotify-Key"))
+	}
+
+	var message gotifyMessage
+	if err := json.Unmarshal(request.body, &message); err != nil {
+		t.Fatalf("invalid payload: %v", err)
+	}
+	if message.Title != "Сайт недоступен" || message.Priority != 8 || !strings.Contains(message.Message, "https://example.com") {
+		t.Errorf("message = %+v", message)
+	}
+}
+
+func TestMatrixChannelSend(t *testing.T) {
+	server, requests := requestCapture(t, http.StatusOK)
+
+	channel, err := newMatrixFactory()(models.User{}, json.RawMessage(`{"homeserver_url": "`+server.URL+`", "access_token": "syt_token", "room_id": "!room:example.com"}`))
+	if err != nil {
+		t.Fatalf("factory error: %v", err)
+	}
+
+	for i := 0; i < 2; i++ {
+		if err := channel.Send(context.Background(), downEvent()); err != nil {
+			t.Fatalf("Send() error = %v", err)
+		}
+	}
+
+	prefix := "/_matrix/client/v3/rooms/%21room:example.com/send/m.room.message/"
+	for _, request := range *requests {
+		if request.method != http.MethodPut || !strings.HasPrefix(request.path, prefix) || request.header.Get("Authorization") != "Bearer syt_token" {
+			t.Errorf("request = %s %s, Authorization %q", request.method, request.path, request.header.Get("Authorization"))
+		}
+	}
+	if (*requests)[0].path == (*requests)[1].path {
+		t.Errorf("transaction ID reused: %s", (*requests)[0].path)
+	}
+
+	// Повторная попытка уведомления из очереди идет с тем же ID транзакции
+	queued := downEvent()
+	queued.NotificationID = 42
+	for i := 0; i < 2; i++ {
+		if err := channel.Send(context.Background(), queued); err != nil {
+			t.Fatalf("Send() error = %v", err)
+		}
+	}
+	if first, retry := (*requests)[2].path, (*requests)[3].path; first != prefix+"uptime-monitor-42" || retry != first {
+		t.Errorf("queued transaction IDs = %s, %s, want %suptime-monitor-42", first, retry, prefix)
+	}
+
+	var message matrixMessage
+	if err := json.Unmarshal((*requests)[0].body, &message); err != nil {
+		t.Fatalf("invalid payload: %v", err)
+	}
+	if message.MsgType != "m.text" || message.Format != "org.matrix.custom.html" ||
+		!strings.Contains(message.Body, "Причина: HTTP 503 <Service Unavailable>") ||
+		!strings.Contains(message.FormattedBody, "<b>Причина:</b> HTTP 503 &lt;Service Unavailable&gt;") {
+		t.Errorf("message = %+v", message)
+	}
+}
+
+func TestSelfHostedFactories(t *testing.T) {
+	tests := []struct {
+		name    string
+		factory Factory
+		config  string
+		wantErr bool
+	}{
+		{name: "ntfy", factory: newNtfyFactory(), config: `{"topic_url": "https://ntfy.sh/alerts"}`},
+		{name: "ntfy without topic", factory: newNtfyFactory(), config: `{"topic_url": "https://ntfy.sh/"}`, wantErr: true},
+		{name: "Gotify without token", factory: newGotifyFactory(), config: `{"server_url": "https://gotify.example.com"}`, wantErr: true},
+		{name: "Matrix room alias", factory: newMatrixFactory(),
+			config: `{"homeserver_url": "https://matrix.example.com", "access_token": "t", "room_id": "#ops:example.com"}`, wantErr: true},
+		{name: "Matrix without token", factory: newMatrixFactory(),
+			config: `{"homeserver_url": "https://matrix.example.com", "room_id": "!room:example.com"}`, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := tt.factory(models.User{}, json.RawMessage(tt.config))
+			if (err != nil) != tt.wantErr {
+				t.Errorf("factory error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestTestEvent(t *testing.T) {
+	server, requests := requestCapture(t, http.StatusOK)
+
+	if _, _, _, err := renderEmail(NewTestEvent()); err != nil {
+		t.Errorf("renderEmail() error = %v", err)
+	}
+
+	// Тестовое оповещение дежурных только открывается, закрывают его вручную
+	pagerDuty, _ := newPagerDutyFactory(server.URL)(models.User{}, json.RawMessage(`{"routing_key": "key"}`))
+	opsgenie, _ := newOpsgenieFactory(server.URL)(models.User{}, json.RawMessage(`{"api_key": "key"}`))
+	for _, channel := range []Channel{pagerDuty, opsgenie} {
+		if err := channel.Send(context.Background(), NewTestEvent()); err != nil {
+			t.Fatalf("Send() error = %v", err)
+		}
+	}
+
+	if len(*requests) != 2 {
+		t.Fatalf("got %d requests, want one trigger per channel", len(*requests))
+	}
+	trigger, create := (*requests)[0].fields(t), (*requests)[1].fields(t)
+	if trigger["event_action"] != "trigger" || trigger["dedup_key"] != "uptime-monitor-test" {
+		t.Errorf("PagerDuty test event = %v", trigger)
+	}
+	if (*requests)[1].path != "/v2/alerts" || create["alias"] != "uptime-monitor-test" {
+		t.Errorf("Opsgenie test alert = %s %v", (*requests)[1].path, create)
+	}
+}
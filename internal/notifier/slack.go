@@ -0,0 +1,104 @@
+package notifier
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/aouxes/uptime-monitor/internal/models"
+)
+
+// ChatWebhookConfig — настройки Slack и Discord каналов: адрес входящего webhook
+type ChatWebhookConfig struct {
+	WebhookURL string `json:"webhook_url"`
+}
+
+// decodeChatWebhookConfig разбирает и проверяет настройки Slack или Discord канала
+func decodeChatWebhookConfig(config json.RawMessage) (string, error) {
+	var cfg ChatWebhookConfig
+	if err := decodeConfig(config, &cfg); err != nil {
+		return "", err
+	}
+
+	if err := validateWebhookURL(cfg.WebhookURL); err != nil {
+		return "", fmt.Errorf("webhook_url: %w", err)
+	}
+
+	return cfg.WebhookURL, nil
+}
+
+type slackChannel struct {
+	client     *http.Client
+	webhookURL string
+}
+
+func newSlackFactory() Factory {
+	client := &http.Client{Timeout: webhookTimeout}
+	return func(owner models.User, config json.RawMessage) (Channel, error) {
+		webhookURL, err := decodeChatWebhookConfig(config)
+		if err != nil {
+			return nil, err
+		}
+		return &slackChannel{client: client, webhookURL: webhookURL}, nil
+	}
+}
+
+// Сообщение Slack: текст для уведомлений и вложение с Block Kit блоками и цветной полосой
+type slackMessage struct {
+	Text        string            `json:"text"`
+	Attachments []slackAttachment `json:"attachments"`
+}
+
+type slackAttachment struct {
+	Color  string       `json:"color"`
+	Blocks []slackBlock `json:"blocks"`
+}
+
+type slackBlock struct {
+	Type   string      `json:"type"`
+	Text   *slackText  `json:"text,omitempty"`
+	Fields []slackText `json:"fields,omitempty"`
+}
+
+type slackText struct {
+	Type string `json:"type"`
+	Text string `json:"text"`
+}
+
+// slackEscape экранирует управляющие символы Slack mrkdwn
+func slackEscape(text string) string {
+	return strings.NewReplacer("&", "&amp;", "<", "&lt;", ">", "&gt;").Replace(text)
+}
+
+// newSlackMessage собирает сообщение Slack о событии
+func newSlackMessage(event Event) slackMessage {
+	title, color := eventTitle(event)
+
+	header := fmt.Sprintf("*%s*\n%s", slackEscape(title), slackEscape(event.Site.URL))
+	blocks := []slackBlock{{Type: "section", Text: &slackText{Type: "mrkdwn", Text: header}}}
+
+	var fields []slackText
+	for _, field := range eventFields(event) {
+		fields = append(fields, slackText{
+			Type: "mrkdwn",
+			Text: fmt.Sprintf("*%s*\n%s", slackEscape(field.Name), slackEscape(field.Value)),
+		})
+	}
+	// В одном блоке Slack допускает не больше 10 полей
+	for len(fields) > 0 {
+		n := min(len(fields), 10)
+		blocks = append(blocks, slackBlock{Type: "section", Fields: fields[:n]})
+		fields = fields[n:]
+	}
+
+	return slackMessage{
+		Text:        fmt.Sprintf("%s: %s", title, event.Site.URL),
+		Attachments: []slackAttachment{{Color: color, Blocks: blocks}},
+	}
+}
+
+func (c *slackChannel) Send(ctx context.Context, event Event) error {
+	return postJSON(ctx, c.client, c.webhookURL, nil, newSlackMessage(event))
+}
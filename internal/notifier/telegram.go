@@ -0,0 +1,57 @@
+package notifier
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/aouxes/uptime-monitor/internal/models"
+	"github.com/aouxes/uptime-monitor/internal/telegram"
+)
+
+// TelegramConfig — настройки Telegram канала.
+// Без chat_id используется чат, связанный с аккаунтом командой /link.
+type TelegramConfig struct {
+	ChatID int64 `json:"chat_id,omitempty"`
+}
+
+type telegramChannel struct {
+	client *telegram.Client
+	chatID int64
+}
+
+// newTelegramFactory создает фабрику Telegram каналов, отправляющих сообщения через client
+func newTelegramFactory(client *telegram.Client) Factory {
+	return func(owner models.User, config json.RawMessage) (Channel, error) {
+		var cfg TelegramConfig
+		if err := decodeConfig(config, &cfg); err != nil {
+			return nil, err
+		}
+
+		chatID := cfg.ChatID
+		if chatID == 0 {
+			chatID = owner.TelegramChatID
+		}
+		if chatID == 0 {
+			return nil, fmt.Errorf("chat_id is required when Telegram account is not linked")
+		}
+
+		return &telegramChannel{client: client, chatID: chatID}, nil
+	}
+}
+
+func (c *telegramChannel) Send(ctx context.Context, event Event) error {
+	switch event.Type {
+	case EventStatusChange:
+		return c.client.SendSiteStatusNotification(ctx, c.chatID, &event.Site, event.OldStatus, event.NewStatus,
+			event.Reason, event.OccurredAt, event.IncidentID, event.Dependents)
+	case EventEscalation:
+		return c.client.SendEscalationNotification(ctx, c.chatID, &event.Site, event.Reason, event.OccurredAt,
+			event.IncidentID, event.EscalationStep)
+	case EventCertExpiry:
+		return c.client.SendCertificateExpiryNotification(ctx, c.chatID, event.Site.URL, *event.CertNotAfter, event.CertDaysLeft)
+	case EventTest:
+		return c.client.SendMessage(ctx, c.chatID, "🔔 <b>ТЕСТОВОЕ УВЕДОМЛЕНИЕ</b>\n\nКанал уведомлений настроен правильно")
+	}
+	return fmt.Errorf("unsupported event type %q", event.Type)
+}
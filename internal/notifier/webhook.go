@@ -0,0 +1,160 @@
+package notifier
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/aouxes/uptime-monitor/internal/models"
+)
+
+// webhookTimeout — таймаут одной попытки доставки, повторы выполняет очередь уведомлений
+const webhookTimeout = 10 * time.Second
+
+// SignatureHeader — заголовок с HMAC-SHA256 подписью тела запроса: "sha256=<hex>"
+const SignatureHeader = "X-Uptime-Signature"
+
+// WebhookConfig — настройки webhook канала
+type WebhookConfig struct {
+	URL    string `json:"url"`
+	Secret string `json:"secret"` // ключ HMAC подписи
+}
+
+// WebhookPayload — тело запроса webhook
+type WebhookPayload struct {
+	Event      string              `json:"event"` // status_change, escalation или cert_expiry
+	OccurredAt time.Time           `json:"occurred_at"`
+	Site       WebhookSite         `json:"site"`
+	OldStatus  string              `json:"old_status,omitempty"`
+	NewStatus  string              `json:"new_status,omitempty"`
+	Error      string              `json:"error,omitempty"` // причина сбоя из результата проверки
+	IncidentID int                 `json:"incident_id,omitempty"`
+	Escalation int                 `json:"escalation_step,omitempty"` // шаг эскалации, начиная с 1
+	Dependents []WebhookSite       `json:"dependents,omitempty"`
+	Cert       *WebhookCertificate `json:"certificate,omitempty"`
+}
+
+// WebhookSite — сайт в теле webhook
+type WebhookSite struct {
+	ID          int       `json:"id"`
+	URL         string    `json:"url"`
+	MonitorType string    `json:"monitor_type"`
+	LastChecked time.Time `json:"last_checked"`
+}
+
+// WebhookCertificate — сертификат в событии cert_expiry
+type WebhookCertificate struct {
+	NotAfter time.Time `json:"not_after"`
+	DaysLeft int       `json:"days_left"`
+}
+
+type webhookChannel struct {
+	client *http.Client
+	url    string
+	secret string
+}
+
+func newWebhookFactory() Factory {
+	client := &http.Client{Timeout: webhookTimeout}
+	return func(owner models.User, config json.RawMessage) (Channel, error) {
+		var cfg WebhookConfig
+		if err := decodeConfig(config, &cfg); err != nil {
+			return nil, err
+		}
+
+		if err := validateWebhookURL(cfg.URL); err != nil {
+			return nil, err
+		}
+		if cfg.Secret == "" {
+			return nil, fmt.Errorf("secret is required")
+		}
+
+		return &webhookChannel{client: client, url: cfg.URL, secret: cfg.Secret}, nil
+	}
+}
+
+// validateWebhookURL проверяет, что адрес — абсолютный http(s) URL
+func validateWebhookURL(rawURL string) error {
+	parsed, err := url.Parse(rawURL)
+	if err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" {
+		return fmt.Errorf("url must be an absolute http or https URL")
+	}
+	return nil
+}
+
+// newWebhookPayload собирает тело webhook по событию
+func newWebhookPayload(event Event) WebhookPayload {
+	payload := WebhookPayload{
+		Event:      event.Type,
+		OccurredAt: event.OccurredAt.UTC(),
+		Site:       newWebhookSite(event.Site),
+		OldStatus:  event.OldStatus,
+		NewStatus:  event.NewStatus,
+		Error:      event.Reason,
+		IncidentID: event.IncidentID,
+		Escalation: event.EscalationStep,
+	}
+
+	for _, dependent := range event.Dependents {
+		payload.Dependents = append(payload.Dependents, newWebhookSite(dependent))
+	}
+
+	if event.CertNotAfter != nil {
+		payload.Cert = &WebhookCertificate{NotAfter: event.CertNotAfter.UTC(), DaysLeft: event.CertDaysLeft}
+	}
+
+	return payload
+}
+
+func newWebhookSite(site models.Site) WebhookSite {
+	return WebhookSite{
+		ID:          site.ID,
+		URL:         site.URL,
+		MonitorType: site.MonitorType,
+		LastChecked: site.LastChecked.UTC(),
+	}
+}
+
+// Sign возвращает значение SignatureHeader для тела body
+func Sign(secret string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+func (c *webhookChannel) Send(ctx context.Context, event Event) error {
+	body, err := json.Marshal(newWebhookPayload(event))
+	if err != nil {
+		return fmt.Errorf("failed to marshal webhook payload: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.url, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "UptimeMonitor/1.0")
+	req.Header.Set("X-Uptime-Event", event.Type)
+	req.Header.Set(SignatureHeader, Sign(c.secret, body))
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to send request: %w", err)
+	}
+	defer resp.Body.Close()
+	io.Copy(io.Discard, io.LimitReader(resp.Body, 64*1024))
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("webhook returned status %d", resp.StatusCode)
+	}
+
+	return nil
+}
@@ -0,0 +1,89 @@
+package notifier
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/aouxes/uptime-monitor/internal/models"
+)
+
+func TestWebhookChannelSend(t *testing.T) {
+	tests := []struct {
+		name    string
+		status  int
+		wantErr bool
+	}{
+		{name: "Delivered", status: http.StatusNoContent},
+		{name: "Rejected", status: http.StatusBadGateway, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server, requests := requestCapture(t, tt.status)
+
+			channel, err := newWebhookFactory()(models.User{}, json.RawMessage(`{"url": "`+server.URL+`", "secret": "s3cret"}`))
+			if err != nil {
+				t.Fatalf("factory error: %v", err)
+			}
+			err = channel.Send(context.Background(), downEvent())
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Send() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			// Повторы выполняет очередь уведомлений, канал делает одну попытку
+			if len(*requests) != 1 {
+				t.Fatalf("attempts = %d, want 1", len(*requests))
+			}
+			request := (*requests)[0]
+			if signature := request.header.Get(SignatureHeader); signature != Sign("s3cret", request.body) {
+				t.Errorf("signature = %q, want %q", signature, Sign("s3cret", request.body))
+			}
+			if request.header.Get("X-Uptime-Event") != EventStatusChange {
+				t.Errorf("X-Uptime-Event = %q", request.header.Get("X-Uptime-Event"))
+			}
+
+			var payload WebhookPayload
+			if err := json.Unmarshal(request.body, &payload); err != nil {
+				t.Fatalf("invalid payload: %v", err)
+			}
+			if payload.Event != EventStatusChange || payload.Site.ID != 7 || payload.Site.URL != "https://example.com" ||
+				payload.OldStatus != "UP" || payload.NewStatus != "DOWN" || payload.Error != "HTTP 503 <Service Unavailable>" ||
+				payload.IncidentID != 12 || len(payload.Dependents) != 1 || payload.Dependents[0].ID != 8 {
+				t.Errorf("unexpected payload: %s", request.body)
+			}
+		})
+	}
+}
+
+func TestSign(t *testing.T) {
+	// echo -n '{"event":"status_change"}' | openssl dgst -sha256 -hmac secret
+	want := "sha256=2014ffe387ef37a9bb3dd37833e11fb33a26764fd77e321741efb2420b3be732"
+	if got := Sign("secret", []byte(`{"event":"status_change"}`)); got != want {
+		t.Errorf("Sign() = %q, want %q", got, want)
+	}
+}
+
+func TestWebhookFactory(t *testing.T) {
+	tests := []struct {
+		name    string
+		config  string
+		wantErr bool
+	}{
+		{name: "Valid", config: `{"url": "https://hooks.example.com/uptime", "secret": "s3cret"}`},
+		{name: "No secret", config: `{"url": "https://hooks.example.com/uptime"}`, wantErr: true},
+		{name: "No URL", config: `{"secret": "s3cret"}`, wantErr: true},
+		{name: "Relative URL", config: `{"url": "/uptime", "secret": "s3cret"}`, wantErr: true},
+		{name: "Unsupported scheme", config: `{"url": "ftp://example.com", "secret": "s3cret"}`, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := newWebhookFactory()(models.User{}, json.RawMessage(tt.config))
+			if (err != nil) != tt.wantErr {
+				t.Errorf("factory error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
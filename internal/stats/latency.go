@@ -0,0 +1,19 @@
+package stats
+
+import "time"
+
+// latencyBuckets — размер интервала агрегации времени ответа для каждого периода
+var latencyBuckets = map[string]time.Duration{
+	"24h": 5 * time.Minute,
+	"7d":  time.Hour,
+	"30d": 4 * time.Hour,
+	"90d": 12 * time.Hour,
+}
+
+// LatencyBucket возвращает размер интервала агрегации для периода
+func LatencyBucket(window string) time.Duration {
+	if bucket, ok := latencyBuckets[window]; ok {
+		return bucket
+	}
+	return latencyBuckets["24h"]
+}
@@ -0,0 +1,117 @@
+package stats
+
+import (
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/aouxes/uptime-monitor/internal/models"
+)
+
+// Windows — поддерживаемые периоды отчетов
+var Windows = map[string]time.Duration{
+	"24h": 24 * time.Hour,
+	"7d":  7 * 24 * time.Hour,
+	"30d": 30 * 24 * time.Hour,
+	"90d": 90 * 24 * time.Hour,
+}
+
+// ParseWindow возвращает длительность периода по его названию
+func ParseWindow(window string) (time.Duration, error) {
+	if window == "" {
+		window = "24h"
+	}
+
+	duration, ok := Windows[window]
+	if !ok {
+		return 0, fmt.Errorf("unsupported window %q, expected one of 24h, 7d, 30d, 90d", window)
+	}
+
+	return duration, nil
+}
+
+// CalculateUptime считает доступность по сменам статуса, отсортированным по времени.
+// Каждый статус действует до следующей смены (или до конца периода).
+// Первая точка может быть раньше from — тогда она задает состояние на начало периода.
+// В доступность учитывается только время в статусах UP и DOWN.
+func CalculateUptime(points []models.StatusPoint, from, to time.Time) models.UptimeReport {
+	report := models.UptimeReport{
+		From:          from,
+		To:            to,
+		UptimePercent: 100,
+	}
+
+	var upTime, downTime time.Duration
+	prevStatus := ""
+
+	for i, point := range points {
+		report.Checks += point.Checks
+
+		start := point.CheckedAt
+		if start.Before(from) {
+			start = from
+		}
+
+		end := to
+		if i+1 < len(points) && points[i+1].CheckedAt.Before(to) {
+			end = points[i+1].CheckedAt
+		}
+
+		if !end.After(start) {
+			continue
+		}
+
+		switch point.Status {
+		case "UP":
+			upTime += end.Sub(start)
+		case "DOWN":
+			downTime += end.Sub(start)
+			if prevStatus != "DOWN" {
+				report.Outages++
+			}
+		default:
+			// Остальные статусы не влияют на доступность и не прерывают текущий сбой
+			continue
+		}
+		prevStatus = point.Status
+	}
+
+	fillReport(&report, upTime, downTime)
+	return report
+}
+
+// Summarize объединяет отчеты по нескольким сайтам в общий отчет
+func Summarize(reports []models.UptimeReport, from, to time.Time) models.UptimeReport {
+	summary := models.UptimeReport{
+		From:          from,
+		To:            to,
+		UptimePercent: 100,
+	}
+
+	var upTime, downTime time.Duration
+	for _, report := range reports {
+		summary.Checks += report.Checks
+		summary.Outages += report.Outages
+		downTime += time.Duration(report.DowntimeSeconds) * time.Second
+		upTime += time.Duration(report.MonitoredSeconds-report.DowntimeSeconds) * time.Second
+	}
+
+	fillReport(&summary, upTime, downTime)
+	return summary
+}
+
+func fillReport(report *models.UptimeReport, upTime, downTime time.Duration) {
+	monitored := upTime + downTime
+	report.MonitoredSeconds = int64(monitored.Seconds())
+	report.DowntimeSeconds = int64(downTime.Seconds())
+
+	if monitored > 0 {
+		percent := float64(upTime) / float64(monitored) * 100
+		report.UptimePercent = math.Round(percent*1000) / 1000
+	}
+
+	if report.Outages > 0 {
+		report.MTTRSeconds = int64(downTime.Seconds()) / int64(report.Outages)
+		report.MTBFSeconds = int64(upTime.Seconds()) / int64(report.Outages)
+	}
+}
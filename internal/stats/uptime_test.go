@@ -0,0 +1,150 @@
+package stats
+
+import (
+	"testing"
+	"time"
+
+	"github.com/aouxes/uptime-monitor/internal/models"
+)
+
+func TestCalculateUptime(t *testing.T) {
+	from := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	to := from.Add(10 * time.Hour)
+	at := func(hours int) time.Time { return from.Add(time.Duration(hours) * time.Hour) }
+
+	tests := []struct {
+		name     string
+		points   []models.StatusPoint
+		checks   int
+		uptime   float64
+		downtime int64
+		outages  int
+		mttr     int64
+		mtbf     int64
+	}{
+		{
+			name:   "No data",
+			points: nil,
+			uptime: 100,
+		},
+		{
+			name: "Always up",
+			points: []models.StatusPoint{
+				{CheckedAt: at(0), Status: "UP"},
+				{CheckedAt: at(5), Status: "UP"},
+			},
+			uptime: 100,
+		},
+		{
+			name: "Two outages",
+			points: []models.StatusPoint{
+				{CheckedAt: at(0), Status: "UP"},
+				{CheckedAt: at(2), Status: "DOWN"},
+				{CheckedAt: at(3), Status: "DOWN"},
+				{CheckedAt: at(4), Status: "UP"},
+				{CheckedAt: at(8), Status: "DOWN"},
+				{CheckedAt: at(9), Status: "UP"},
+			},
+			uptime:   70,
+			downtime: 3 * 3600,
+			outages:  2,
+			mttr:     3 * 3600 / 2,
+			mtbf:     7 * 3600 / 2,
+		},
+		{
+			name: "Point before window sets initial state",
+			points: []models.StatusPoint{
+				{CheckedAt: from.Add(-time.Hour), Status: "DOWN"},
+				{CheckedAt: at(5), Status: "UP"},
+			},
+			uptime:   50,
+			downtime: 5 * 3600,
+			outages:  1,
+			mttr:     5 * 3600,
+			mtbf:     5 * 3600,
+		},
+		{
+			name: "Runs of checks",
+			points: []models.StatusPoint{
+				{CheckedAt: from.Add(-time.Hour), Status: "UP", Checks: 6},
+				{CheckedAt: at(6), Status: "DOWN", Checks: 4},
+			},
+			checks:   10,
+			uptime:   60,
+			downtime: 4 * 3600,
+			outages:  1,
+			mttr:     4 * 3600,
+			mtbf:     6 * 3600,
+		},
+		{
+			name: "Unknown status is not counted",
+			points: []models.StatusPoint{
+				{CheckedAt: at(0), Status: "UNKNOWN"},
+				{CheckedAt: at(5), Status: "DOWN"},
+			},
+			uptime:   0,
+			downtime: 5 * 3600,
+			outages:  1,
+			mttr:     5 * 3600,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			report := CalculateUptime(tt.points, from, to)
+
+			if report.Checks != tt.checks {
+				t.Errorf("Expected %d checks, got %d", tt.checks, report.Checks)
+			}
+			if report.UptimePercent != tt.uptime {
+				t.Errorf("Expected uptime %v, got %v", tt.uptime, report.UptimePercent)
+			}
+			if report.DowntimeSeconds != tt.downtime {
+				t.Errorf("Expected downtime %d, got %d", tt.downtime, report.DowntimeSeconds)
+			}
+			if report.Outages != tt.outages {
+				t.Errorf("Expected %d outages, got %d", tt.outages, report.Outages)
+			}
+			if report.MTTRSeconds != tt.mttr {
+				t.Errorf("Expected MTTR %d, got %d", tt.mttr, report.MTTRSeconds)
+			}
+			if report.MTBFSeconds != tt.mtbf {
+				t.Errorf("Expected MTBF %d, got %d", tt.mtbf, report.MTBFSeconds)
+			}
+		})
+	}
+}
+
+func TestSummarize(t *testing.T) {
+	from := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	to := from.Add(10 * time.Hour)
+
+	reports := []models.UptimeReport{
+		{Checks: 10, MonitoredSeconds: 36000, DowntimeSeconds: 3600, Outages: 1},
+		{Checks: 10, MonitoredSeconds: 36000, DowntimeSeconds: 0, Outages: 0},
+	}
+
+	summary := Summarize(reports, from, to)
+
+	if summary.UptimePercent != 95 {
+		t.Errorf("Expected uptime 95, got %v", summary.UptimePercent)
+	}
+	if summary.Outages != 1 || summary.MTTRSeconds != 3600 {
+		t.Errorf("Expected 1 outage with MTTR 3600, got %d and %d", summary.Outages, summary.MTTRSeconds)
+	}
+	if summary.Checks != 20 {
+		t.Errorf("Expected 20 checks, got %d", summary.Checks)
+	}
+}
+
+func TestParseWindow(t *testing.T) {
+	if d, err := ParseWindow(""); err != nil || d != 24*time.Hour {
+		t.Errorf("Expected default window 24h, got %v (%v)", d, err)
+	}
+	if d, err := ParseWindow("30d"); err != nil || d != 30*24*time.Hour {
+		t.Errorf("Expected 30d window, got %v (%v)", d, err)
+	}
+	if _, err := ParseWindow("1y"); err == nil {
+		t.Error("Expected error for unsupported window")
+	}
+}
@@ -0,0 +1,101 @@
+package storage
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/aouxes/uptime-monitor/internal/models"
+	"github.com/jackc/pgx/v5"
+)
+
+// UpsertSiteCertificate сохраняет сертификат сайта и возвращает последний порог предупреждения.
+// Если сертификат обновился (изменился срок действия), порог сбрасывается.
+func (s *Storage) UpsertSiteCertificate(ctx context.Context, cert *models.SiteCertificate) (int, error) {
+	query := `
+        INSERT INTO site_certificates (site_id, subject, issuer, sans, not_before, not_after, chain, checked_at)
+        VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+        ON CONFLICT (site_id) DO UPDATE SET
+            subject = EXCLUDED.subject,
+            issuer = EXCLUDED.issuer,
+            sans = EXCLUDED.sans,
+            not_before = EXCLUDED.not_before,
+            not_after = EXCLUDED.not_after,
+            chain = EXCLUDED.chain,
+            checked_at = EXCLUDED.checked_at,
+            last_warned_days = CASE
+                WHEN site_certificates.not_after = EXCLUDED.not_after THEN site_certificates.last_warned_days
+                ELSE 0
+            END
+        RETURNING last_warned_days
+    `
+
+	sans := cert.SANs
+	if sans == nil {
+		sans = []string{}
+	}
+
+	var lastWarnedDays int
+	err := s.db.QueryRow(ctx, query,
+		cert.SiteID,
+		cert.Subject,
+		cert.Issuer,
+		sans,
+		cert.NotBefore,
+		cert.NotAfter,
+		cert.Chain,
+		cert.CheckedAt,
+	).Scan(&lastWarnedDays)
+
+	if err != nil {
+		return 0, fmt.Errorf("failed to upsert site certificate: %w", err)
+	}
+
+	return lastWarnedDays, nil
+}
+
+// SetCertificateWarned запоминает порог, о котором предупреждает notification,
+// и тем же запросом ставит предупреждение в очередь
+func (s *Storage) SetCertificateWarned(ctx context.Context, siteID int, days int, notification *models.Notification) error {
+	query := `
+        WITH target AS (
+            UPDATE site_certificates SET last_warned_days = $9 WHERE site_id = $10
+            RETURNING site_id AS id
+        )` + enqueueNotification
+
+	args := append(notificationArgs(notification), days, siteID)
+	_, err := s.db.Exec(ctx, query, args...)
+	if err != nil {
+		return fmt.Errorf("failed to update certificate warning: %w", err)
+	}
+
+	return nil
+}
+
+func (s *Storage) GetSiteCertificate(ctx context.Context, siteID int) (*models.SiteCertificate, error) {
+	query := `
+        SELECT site_id, subject, issuer, sans, not_before, not_after, chain, checked_at
+        FROM site_certificates
+        WHERE site_id = $1
+    `
+
+	var cert models.SiteCertificate
+	err := s.db.QueryRow(ctx, query, siteID).Scan(
+		&cert.SiteID,
+		&cert.Subject,
+		&cert.Issuer,
+		&cert.SANs,
+		&cert.NotBefore,
+		&cert.NotAfter,
+		&cert.Chain,
+		&cert.CheckedAt,
+	)
+
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get site certificate: %w", err)
+	}
+
+	return &cert, nil
+}
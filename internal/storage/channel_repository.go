@@ -0,0 +1,184 @@
+package storage
+
+import (
+	"context"
+	"fmt"
+	"log"
+
+	"github.com/aouxes/uptime-monitor/internal/models"
+	"github.com/jackc/pgx/v5"
+)
+
+const channelColumns = `c.id, c.user_id, c.type, c.name, c.config, c.is_default, c.enabled, c.created_at`
+
+func channelFields(channel *models.NotificationChannel) []interface{} {
+	return []interface{}{
+		&channel.ID,
+		&channel.UserID,
+		&channel.Type,
+		&channel.Name,
+		&channel.Config,
+		&channel.IsDefault,
+		&channel.Enabled,
+		&channel.CreatedAt,
+	}
+}
+
+// CreateNotificationChannel сохраняет канал уведомлений пользователя
+func (s *Storage) CreateNotificationChannel(ctx context.Context, channel *models.NotificationChannel) error {
+	query := `
+        INSERT INTO notification_channels (user_id, type, name, config, is_default, enabled)
+        VALUES ($1, $2, $3, $4, $5, $6)
+        RETURNING id, created_at
+    `
+
+	err := s.db.QueryRow(ctx, query,
+		channel.UserID,
+		channel.Type,
+		channel.Name,
+		channel.Config,
+		channel.IsDefault,
+		channel.Enabled,
+	).Scan(&channel.ID, &channel.CreatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to create notification channel: %w", err)
+	}
+
+	log.Printf("Notification channel created: ID=%d, UserID=%d, Type=%s", channel.ID, channel.UserID, channel.Type)
+	return nil
+}
+
+// UpdateNotificationChannel обновляет канал уведомлений пользователя.
+// Возвращает false, если канал не найден.
+func (s *Storage) UpdateNotificationChannel(ctx context.Context, channel *models.NotificationChannel) (bool, error) {
+	query := `
+        UPDATE notification_channels
+        SET type = $3, name = $4, config = $5, is_default = $6, enabled = $7
+        WHERE id = $1 AND user_id = $2
+        RETURNING created_at
+    `
+
+	err := s.db.QueryRow(ctx, query,
+		channel.ID,
+		channel.UserID,
+		channel.Type,
+		channel.Name,
+		channel.Config,
+		channel.IsDefault,
+		channel.Enabled,
+	).Scan(&channel.CreatedAt)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return false, nil
+		}
+		return false, fmt.Errorf("failed to update notification channel: %w", err)
+	}
+
+	return true, nil
+}
+
+// DeleteNotificationChannel удаляет канал уведомлений пользователя.
+// Возвращает false, если канал не найден.
+func (s *Storage) DeleteNotificationChannel(ctx context.Context, userID, channelID int) (bool, error) {
+	result, err := s.db.Exec(ctx, `DELETE FROM notification_channels WHERE id = $1 AND user_id = $2`, channelID, userID)
+	if err != nil {
+		return false, fmt.Errorf("failed to delete notification channel: %w", err)
+	}
+
+	return result.RowsAffected() > 0, nil
+}
+
+// GetUserNotificationChannel возвращает канал уведомлений пользователя или nil, если его нет
+func (s *Storage) GetUserNotificationChannel(ctx context.Context, userID, channelID int) (*models.NotificationChannel, error) {
+	query := `
+        SELECT ` + channelColumns + `
+        FROM notification_channels c
+        WHERE c.id = $1 AND c.user_id = $2
+    `
+
+	var channel models.NotificationChannel
+	err := s.db.QueryRow(ctx, query, channelID, userID).Scan(channelFields(&channel)...)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get notification channel: %w", err)
+	}
+
+	return &channel, nil
+}
+
+// GetUserNotificationChannels возвращает все каналы уведомлений пользователя
+func (s *Storage) GetUserNotificationChannels(ctx context.Context, userID int) ([]models.NotificationChannel, error) {
+	query := `
+        SELECT ` + channelColumns + `
+        FROM notification_channels c
+        WHERE c.user_id = $1
+        ORDER BY c.id
+    `
+
+	return s.queryNotificationChannels(ctx, query, userID)
+}
+
+// GetSiteNotificationChannels возвращает включенные каналы, в которые уходят уведомления сайта:
+// каналы из маршрутизации сайта, а если она не задана — каналы пользователя по умолчанию
+func (s *Storage) GetSiteNotificationChannels(ctx context.Context, site *models.Site) ([]models.NotificationChannel, error) {
+	query := `
+        SELECT ` + channelColumns + `
+        FROM notification_channels c
+        WHERE c.user_id = $1 AND c.enabled
+            AND CASE WHEN cardinality($2::int[]) > 0 THEN c.id = ANY($2) ELSE c.is_default END
+        ORDER BY c.id
+    `
+
+	channelIDs := site.NotificationChannelIDs
+	if channelIDs == nil {
+		channelIDs = []int{}
+	}
+
+	return s.queryNotificationChannels(ctx, query, site.UserID, channelIDs)
+}
+
+// SetSiteNotificationChannels заменяет маршрутизацию уведомлений сайта.
+// Каналы, не принадлежащие владельцу сайта, игнорируются.
+func (s *Storage) SetSiteNotificationChannels(ctx context.Context, siteID, userID int, channelIDs []int) error {
+	query := `
+        WITH unlinked AS (
+            DELETE FROM site_notification_channels
+            WHERE site_id = $1 AND channel_id <> ALL($3)
+        )
+        INSERT INTO site_notification_channels (site_id, channel_id)
+        SELECT $1, c.id FROM notification_channels c
+        WHERE c.id = ANY($3) AND c.user_id = $2
+        ON CONFLICT DO NOTHING
+    `
+
+	if channelIDs == nil {
+		channelIDs = []int{}
+	}
+
+	if _, err := s.db.Exec(ctx, query, siteID, userID, channelIDs); err != nil {
+		return fmt.Errorf("failed to set site notification channels: %w", err)
+	}
+
+	return nil
+}
+
+func (s *Storage) queryNotificationChannels(ctx context.Context, query string, args ...interface{}) ([]models.NotificationChannel, error) {
+	rows, err := s.db.Query(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get notification channels: %w", err)
+	}
+	defer rows.Close()
+
+	channels := []models.NotificationChannel{}
+	for rows.Next() {
+		var channel models.NotificationChannel
+		if err := rows.Scan(channelFields(&channel)...); err != nil {
+			return nil, fmt.Errorf("failed to scan notification channel: %w", err)
+		}
+		channels = append(channels, channel)
+	}
+
+	return channels, rows.Err()
+}
@@ -0,0 +1,182 @@
+package storage
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/aouxes/uptime-monitor/internal/models"
+)
+
+func (s *Storage) CreateCheckResult(ctx context.Context, result *models.CheckResult) error {
+	query := `
+        INSERT INTO check_results (site_id, checked_at, status, status_code, response_time_ms,
+            dns_ms, connect_ms, tls_ms, ttfb_ms, error, assertion_failures, confirmed_status)
+        VALUES ($1, $2, $3, NULLIF($4, 0), $5, $6, $7, $8, $9, NULLIF($10, ''), $11, COALESCE(NULLIF($12, ''), $3))
+        RETURNING id
+    `
+
+	if result.CheckedAt.IsZero() {
+		result.CheckedAt = time.Now()
+	}
+
+	err := s.db.QueryRow(ctx, query,
+		result.SiteID,
+		result.CheckedAt,
+		result.Status,
+		result.StatusCode,
+		result.ResponseTimeMs,
+		result.DNSMs,
+		result.ConnectMs,
+		result.TLSMs,
+		result.TTFBMs,
+		result.Error,
+		result.AssertionFailures,
+		result.ConfirmedStatus,
+	).Scan(&result.ID)
+
+	if err != nil {
+		return fmt.Errorf("failed to create check result: %w", err)
+	}
+
+	return nil
+}
+
+// GetSiteCheckResults возвращает историю проверок сайта начиная с since (новые первыми)
+func (s *Storage) GetSiteCheckResults(ctx context.Context, siteID int, since time.Time, limit int) ([]models.CheckResult, error) {
+	query := `
+        SELECT id, site_id, checked_at, status, COALESCE(status_code, 0), response_time_ms,
+            dns_ms, connect_ms, tls_ms, ttfb_ms, COALESCE(error, ''), assertion_failures, confirmed_status
+        FROM check_results
+        WHERE site_id = $1 AND checked_at >= $2
+        ORDER BY checked_at DESC
+        LIMIT $3
+    `
+
+	rows, err := s.db.Query(ctx, query, siteID, since, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get check results: %w", err)
+	}
+	defer rows.Close()
+
+	var results []models.CheckResult
+	for rows.Next() {
+		var result models.CheckResult
+		err := rows.Scan(
+			&result.ID,
+			&result.SiteID,
+			&result.CheckedAt,
+			&result.Status,
+			&result.StatusCode,
+			&result.ResponseTimeMs,
+			&result.DNSMs,
+			&result.ConnectMs,
+			&result.TLSMs,
+			&result.TTFBMs,
+			&result.Error,
+			&result.AssertionFailures,
+			&result.ConfirmedStatus,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan check result: %w", err)
+		}
+		results = append(results, result)
+	}
+
+	return results, rows.Err()
+}
+
+// GetStatusPoints возвращает смены подтвержденного статуса сайтов начиная с from,
+// плюс последнюю проверку до from, чтобы знать состояние на начало периода.
+// Проверки с одинаковым статусом подряд сворачиваются в одну точку на стороне базы.
+func (s *Storage) GetStatusPoints(ctx context.Context, siteIDs []int, from time.Time) (map[int][]models.StatusPoint, error) {
+	query := `
+        WITH checks AS (
+            SELECT site_id, checked_at, confirmed_status FROM (
+                SELECT DISTINCT ON (site_id) site_id, checked_at, confirmed_status
+                FROM check_results
+                WHERE site_id = ANY($1) AND checked_at < $2
+                ORDER BY site_id, checked_at DESC
+            ) previous
+            UNION ALL
+            SELECT site_id, checked_at, confirmed_status
+            FROM check_results
+            WHERE site_id = ANY($1) AND checked_at >= $2
+        ), changes AS (
+            SELECT site_id, checked_at, confirmed_status,
+                confirmed_status IS DISTINCT FROM
+                    LAG(confirmed_status) OVER (PARTITION BY site_id ORDER BY checked_at) AS changed
+            FROM checks
+        ), runs AS (
+            SELECT site_id, checked_at, confirmed_status,
+                COUNT(*) FILTER (WHERE changed) OVER (PARTITION BY site_id ORDER BY checked_at) AS run
+            FROM changes
+        )
+        SELECT site_id, MIN(checked_at), confirmed_status, COUNT(*) FILTER (WHERE checked_at >= $2)
+        FROM runs
+        GROUP BY site_id, run, confirmed_status
+        ORDER BY site_id, MIN(checked_at)
+    `
+
+	rows, err := s.db.Query(ctx, query, siteIDs, from)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get status points: %w", err)
+	}
+	defer rows.Close()
+
+	points := make(map[int][]models.StatusPoint)
+	for rows.Next() {
+		var point models.StatusPoint
+		if err := rows.Scan(&point.SiteID, &point.CheckedAt, &point.Status, &point.Checks); err != nil {
+			return nil, fmt.Errorf("failed to scan status point: %w", err)
+		}
+		points[point.SiteID] = append(points[point.SiteID], point)
+	}
+
+	return points, rows.Err()
+}
+
+// GetLatencySeries возвращает среднее время ответа успешных проверок, сгруппированное по интервалам bucket
+func (s *Storage) GetLatencySeries(ctx context.Context, siteID int, from time.Time, bucket time.Duration) ([]models.LatencyPoint, error) {
+	query := `
+        SELECT date_bin(make_interval(secs => $3::bigint), checked_at, TIMESTAMPTZ '2000-01-01') AS bucket,
+            COUNT(*),
+            AVG(response_time_ms)::BIGINT,
+            MAX(response_time_ms)::BIGINT,
+            AVG(dns_ms)::BIGINT,
+            AVG(connect_ms)::BIGINT,
+            AVG(tls_ms)::BIGINT,
+            AVG(ttfb_ms)::BIGINT
+        FROM check_results
+        WHERE site_id = $1 AND checked_at >= $2 AND status = 'UP'
+        GROUP BY bucket
+        ORDER BY bucket
+    `
+
+	rows, err := s.db.Query(ctx, query, siteID, from, int64(bucket.Seconds()))
+	if err != nil {
+		return nil, fmt.Errorf("failed to get latency series: %w", err)
+	}
+	defer rows.Close()
+
+	var points []models.LatencyPoint
+	for rows.Next() {
+		var point models.LatencyPoint
+		err := rows.Scan(
+			&point.Time,
+			&point.Checks,
+			&point.AvgMs,
+			&point.MaxMs,
+			&point.DNSMs,
+			&point.ConnectMs,
+			&point.TLSMs,
+			&point.TTFBMs,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan latency point: %w", err)
+		}
+		points = append(points, point)
+	}
+
+	return points, rows.Err()
+}
@@ -0,0 +1,239 @@
+package storage
+
+import (
+	"context"
+	"fmt"
+	"log"
+
+	"github.com/aouxes/uptime-monitor/internal/models"
+	"github.com/jackc/pgx/v5"
+)
+
+const escalationPolicyColumns = `
+    p.id, p.user_id, p.name, p.steps, p.repeat_steps, p.created_at,
+    COALESCE((SELECT json_agg(ps.site_id ORDER BY ps.site_id)
+        FROM escalation_policy_sites ps WHERE ps.policy_id = p.id), '[]')`
+
+func escalationPolicyFields(policy *models.EscalationPolicy) []interface{} {
+	return []interface{}{
+		&policy.ID,
+		&policy.UserID,
+		&policy.Name,
+		&policy.Steps,
+		&policy.Repeat,
+		&policy.CreatedAt,
+		&policy.SiteIDs,
+	}
+}
+
+// CreateEscalationPolicy сохраняет политику эскалации и привязывает к ней сайты пользователя.
+// Сайт, привязанный к другой политике, переходит в новую.
+func (s *Storage) CreateEscalationPolicy(ctx context.Context, policy *models.EscalationPolicy) error {
+	query := `
+        WITH created AS (
+            INSERT INTO escalation_policies (user_id, name, steps, repeat_steps)
+            VALUES ($1, $2, $3, $4)
+            RETURNING id, created_at
+        ), linked AS (
+            INSERT INTO escalation_policy_sites (site_id, policy_id)
+            SELECT s.id, c.id FROM created c JOIN sites s ON s.id = ANY($5) AND s.user_id = $1
+            ON CONFLICT (site_id) DO UPDATE SET policy_id = EXCLUDED.policy_id
+        )
+        SELECT id, created_at FROM created
+    `
+
+	if policy.SiteIDs == nil {
+		policy.SiteIDs = []int{}
+	}
+
+	err := s.db.QueryRow(ctx, query,
+		policy.UserID,
+		policy.Name,
+		policy.Steps,
+		policy.Repeat,
+		policy.SiteIDs,
+	).Scan(&policy.ID, &policy.CreatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to create escalation policy: %w", err)
+	}
+
+	log.Printf("Escalation policy created: ID=%d, UserID=%d, Steps=%d", policy.ID, policy.UserID, len(policy.Steps))
+	return nil
+}
+
+// UpdateEscalationPolicy обновляет политику эскалации пользователя и заменяет список ее сайтов.
+// Возвращает false, если политика не найдена.
+func (s *Storage) UpdateEscalationPolicy(ctx context.Context, policy *models.EscalationPolicy) (bool, error) {
+	query := `
+        WITH updated AS (
+            UPDATE escalation_policies SET name = $3, steps = $4, repeat_steps = $5
+            WHERE id = $1 AND user_id = $2
+            RETURNING id, created_at
+        ), unlinked AS (
+            DELETE FROM escalation_policy_sites
+            WHERE policy_id IN (SELECT id FROM updated) AND site_id <> ALL($6)
+        ), linked AS (
+            INSERT INTO escalation_policy_sites (site_id, policy_id)
+            SELECT s.id, u.id FROM updated u JOIN sites s ON s.id = ANY($6) AND s.user_id = $2
+            ON CONFLICT (site_id) DO UPDATE SET policy_id = EXCLUDED.policy_id
+        )
+        SELECT created_at FROM updated
+    `
+
+	if policy.SiteIDs == nil {
+		policy.SiteIDs = []int{}
+	}
+
+	err := s.db.QueryRow(ctx, query,
+		policy.ID,
+		policy.UserID,
+		policy.Name,
+		policy.Steps,
+		policy.Repeat,
+		policy.SiteIDs,
+	).Scan(&policy.CreatedAt)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return false, nil
+		}
+		return false, fmt.Errorf("failed to update escalation policy: %w", err)
+	}
+
+	return true, nil
+}
+
+// DeleteEscalationPolicy удаляет политику эскалации пользователя.
+// Возвращает false, если политика не найдена.
+func (s *Storage) DeleteEscalationPolicy(ctx context.Context, userID, policyID int) (bool, error) {
+	result, err := s.db.Exec(ctx, `DELETE FROM escalation_policies WHERE id = $1 AND user_id = $2`, policyID, userID)
+	if err != nil {
+		return false, fmt.Errorf("failed to delete escalation policy: %w", err)
+	}
+
+	return result.RowsAffected() > 0, nil
+}
+
+// GetUserEscalationPolicies возвращает политики эскалации пользователя, новые первыми
+func (s *Storage) GetUserEscalationPolicies(ctx context.Context, userID int) ([]models.EscalationPolicy, error) {
+	query := `
+        SELECT ` + escalationPolicyColumns + `
+        FROM escalation_policies p
+        WHERE p.user_id = $1
+        ORDER BY p.created_at DESC, p.id DESC
+    `
+
+	rows, err := s.db.Query(ctx, query, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get escalation policies: %w", err)
+	}
+	defer rows.Close()
+
+	policies := []models.EscalationPolicy{}
+	for rows.Next() {
+		var policy models.EscalationPolicy
+		if err := rows.Scan(escalationPolicyFields(&policy)...); err != nil {
+			return nil, fmt.Errorf("failed to scan escalation policy: %w", err)
+		}
+		policies = append(policies, policy)
+	}
+
+	return policies, rows.Err()
+}
+
+// GetUserEscalationPolicy возвращает политику эскалации пользователя или nil, если ее нет
+func (s *Storage) GetUserEscalationPolicy(ctx context.Context, userID, policyID int) (*models.EscalationPolicy, error) {
+	query := `
+        SELECT ` + escalationPolicyColumns + `
+        FROM escalation_policies p
+        WHERE p.id = $1 AND p.user_id = $2
+    `
+
+	return s.queryEscalationPolicy(ctx, query, policyID, userID)
+}
+
+// GetSiteEscalationPolicy возвращает политику эскалации сайта или nil, если ее нет
+func (s *Storage) GetSiteEscalationPolicy(ctx context.Context, siteID int) (*models.EscalationPolicy, error) {
+	query := `
+        SELECT ` + escalationPolicyColumns + `
+        FROM escalation_policies p
+        JOIN escalation_policy_sites ps ON ps.policy_id = p.id
+        WHERE ps.site_id = $1
+    `
+
+	return s.queryEscalationPolicy(ctx, query, siteID)
+}
+
+func (s *Storage) queryEscalationPolicy(ctx context.Context, query string, args ...interface{}) (*models.EscalationPolicy, error) {
+	var policy models.EscalationPolicy
+	err := s.db.QueryRow(ctx, query, args...).Scan(escalationPolicyFields(&policy)...)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get escalation policy: %w", err)
+	}
+
+	return &policy, nil
+}
+
+// GetPendingEscalations возвращает открытые неподтвержденные инциденты сайтов с политикой эскалации.
+// Пока сайт на обслуживании или недоступен его родитель, инцидент остается открытым, но не эскалируется.
+func (s *Storage) GetPendingEscalations(ctx context.Context) ([]models.IncidentEscalation, error) {
+	query := `
+        SELECT i.id, i.site_id, i.started_at, i.last_error, i.escalation_step, i.escalated_at, ` + escalationPolicyColumns + `
+        FROM incidents i
+        JOIN sites s ON s.id = i.site_id
+        JOIN escalation_policy_sites eps ON eps.site_id = i.site_id
+        JOIN escalation_policies p ON p.id = eps.policy_id
+        WHERE i.resolved_at IS NULL AND i.acknowledged_at IS NULL AND s.last_status = 'DOWN'
+    `
+
+	rows, err := s.db.Query(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get pending escalations: %w", err)
+	}
+	defer rows.Close()
+
+	var escalations []models.IncidentEscalation
+	for rows.Next() {
+		var escalation models.IncidentEscalation
+		fields := append([]interface{}{
+			&escalation.IncidentID,
+			&escalation.SiteID,
+			&escalation.StartedAt,
+			&escalation.LastError,
+			&escalation.Step,
+			&escalation.EscalatedAt,
+		}, escalationPolicyFields(&escalation.Policy)...)
+		if err := rows.Scan(fields...); err != nil {
+			return nil, fmt.Errorf("failed to scan pending escalation: %w", err)
+		}
+		escalations = append(escalations, escalation)
+	}
+
+	return escalations, rows.Err()
+}
+
+// EscalateIncident отмечает выполнение шага эскалации инцидента, записывает его в хронологию
+// и тем же запросом ставит в очередь уведомление шага notification (обязательно).
+// step — сколько шагов было выполнено до этого.
+// Возвращает false, если инцидент уже подтвержден, закрыт или шаг выполнен другим процессом.
+func (s *Storage) EscalateIncident(ctx context.Context, incidentID, step int, message string, notification *models.Notification) (bool, error) {
+	query := `
+        WITH target AS (
+            UPDATE incidents SET escalation_step = escalation_step + 1, escalated_at = NOW()
+            WHERE id = $9 AND escalation_step = $10 AND acknowledged_at IS NULL AND resolved_at IS NULL
+            RETURNING site_id AS id, id AS incident_id
+        ), logged AS (
+            INSERT INTO incident_events (incident_id, created_at, type, message)
+            SELECT incident_id, NOW(), $11, $12 FROM target
+        )` + enqueueNotification
+
+	args := append(notificationArgs(notification), incidentID, step, models.IncidentEventEscalated, message)
+	result, err := s.db.Exec(ctx, query, args...)
+	if err != nil {
+		return false, fmt.Errorf("failed to escalate incident: %w", err)
+	}
+
+	return result.RowsAffected() > 0, nil
+}
@@ -0,0 +1,315 @@
+package storage
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/aouxes/uptime-monitor/internal/models"
+	"github.com/jackc/pgx/v5"
+)
+
+// OpenIncident открывает инцидент сайта, если открытого еще нет. Началом инцидента
+// и первой ошибкой считается первая неудачная проверка после последней успешной
+// (или проверки во время обслуживания либо недоступности родительского сайта).
+// Возвращает ID нового инцидента или 0, если инцидент уже открыт.
+func (s *Storage) OpenIncident(ctx context.Context, siteID int, checkedAt time.Time, reason string) (int, error) {
+	query := `
+        WITH failures AS (
+            SELECT checked_at, COALESCE(error, '') AS error
+            FROM check_results
+            WHERE site_id = $1 AND status NOT IN ('UP', 'MAINTENANCE', 'UNREACHABLE')
+                AND checked_at > COALESCE(
+                    (SELECT MAX(checked_at) FROM check_results
+                        WHERE site_id = $1 AND status IN ('UP', 'MAINTENANCE', 'UNREACHABLE')),
+                    '-infinity')
+        ), first_failure AS (
+            SELECT checked_at, error FROM failures ORDER BY checked_at LIMIT 1
+        ), incident AS (
+            INSERT INTO incidents (site_id, started_at, first_error, last_error, failed_checks)
+            SELECT $1,
+                COALESCE((SELECT checked_at FROM first_failure), $2),
+                COALESCE((SELECT error FROM first_failure), $3),
+                $3,
+                GREATEST((SELECT COUNT(*) FROM failures), 1)
+            ON CONFLICT (site_id) WHERE resolved_at IS NULL DO NOTHING
+            RETURNING id, started_at, first_error
+        )
+        INSERT INTO incident_events (incident_id, created_at, type, message)
+        SELECT id, started_at, $4, first_error FROM incident
+        RETURNING incident_id
+    `
+
+	var incidentID int
+	err := s.db.QueryRow(ctx, query, siteID, checkedAt, reason, models.IncidentEventOpened).Scan(&incidentID)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return 0, nil
+		}
+		return 0, fmt.Errorf("failed to open incident: %w", err)
+	}
+
+	return incidentID, nil
+}
+
+// RecordIncidentFailure учитывает очередной сбой в открытом инциденте сайта.
+// Если причина сбоя изменилась, в хронологию добавляется событие.
+func (s *Storage) RecordIncidentFailure(ctx context.Context, siteID int, checkedAt time.Time, reason string) error {
+	query := `
+        WITH previous AS (
+            SELECT id, last_error FROM incidents
+            WHERE site_id = $1 AND resolved_at IS NULL
+            FOR UPDATE
+        ), updated AS (
+            UPDATE incidents i
+            SET failed_checks = i.failed_checks + 1, last_error = $3
+            FROM previous p
+            WHERE i.id = p.id
+            RETURNING i.id, p.last_error AS previous_error
+        )
+        INSERT INTO incident_events (incident_id, created_at, type, message)
+        SELECT id, $2, $4, $3 FROM updated
+        WHERE previous_error <> $3
+    `
+
+	if _, err := s.db.Exec(ctx, query, siteID, checkedAt, reason, models.IncidentEventErrorChanged); err != nil {
+		return fmt.Errorf("failed to record incident failure: %w", err)
+	}
+
+	return nil
+}
+
+// ResolveIncident закрывает открытый инцидент сайта.
+// Возвращает ID закрытого инцидента или 0, если открытого не было.
+func (s *Storage) ResolveIncident(ctx context.Context, siteID int, resolvedAt time.Time, message string) (int, error) {
+	query := `
+        WITH resolved AS (
+            UPDATE incidents SET resolved_at = $2
+            WHERE site_id = $1 AND resolved_at IS NULL
+            RETURNING id
+        )
+        INSERT INTO incident_events (incident_id, created_at, type, message)
+        SELECT id, $2, $4, $3 FROM resolved
+        RETURNING incident_id
+    `
+
+	var incidentID int
+	err := s.db.QueryRow(ctx, query, siteID, resolvedAt, message, models.IncidentEventResolved).Scan(&incidentID)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return 0, nil
+		}
+		return 0, fmt.Errorf("failed to resolve incident: %w", err)
+	}
+
+	return incidentID, nil
+}
+
+// incidentColumns — колонки инцидента в порядке incidentFields (алиасы: incidents i, sites s)
+const incidentColumns = `i.id, i.site_id, s.url, i.started_at, i.resolved_at,
+            EXTRACT(EPOCH FROM (COALESCE(i.resolved_at, NOW()) - i.started_at))::bigint,
+            i.first_error, i.last_error, i.failed_checks, i.acknowledged_at, i.acknowledged_by`
+
+func incidentFields(incident *models.Incident) []interface{} {
+	return []interface{}{
+		&incident.ID,
+		&incident.SiteID,
+		&incident.SiteURL,
+		&incident.StartedAt,
+		&incident.ResolvedAt,
+		&incident.DurationSeconds,
+		&incident.FirstError,
+		&incident.LastError,
+		&incident.FailedChecks,
+		&incident.AcknowledgedAt,
+		&incident.AcknowledgedBy,
+	}
+}
+
+// GetUserIncidents возвращает инциденты сайтов пользователя (новые первыми)
+func (s *Storage) GetUserIncidents(ctx context.Context, userID int, filter models.IncidentFilter) ([]models.Incident, error) {
+	query := `
+        SELECT ` + incidentColumns + `
+        FROM incidents i
+        JOIN sites s ON s.id = i.site_id
+        WHERE s.user_id = $1
+            AND ($2::int = 0 OR i.site_id = $2)
+            AND ($3::text = ''
+                OR ($3 = 'open' AND i.resolved_at IS NULL)
+                OR ($3 = 'resolved' AND i.resolved_at IS NOT NULL))
+            AND ($4::timestamptz IS NULL OR i.started_at >= $4)
+            AND ($5::timestamptz IS NULL OR i.started_at < $5)
+        ORDER BY i.started_at DESC
+        LIMIT $6
+    `
+
+	rows, err := s.db.Query(ctx, query, userID, filter.SiteID, filter.Status, filter.From, filter.To, filter.Limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get incidents: %w", err)
+	}
+	defer rows.Close()
+
+	incidents := []models.Incident{}
+	for rows.Next() {
+		var incident models.Incident
+		if err := rows.Scan(incidentFields(&incident)...); err != nil {
+			return nil, fmt.Errorf("failed to scan incident: %w", err)
+		}
+		incidents = append(incidents, incident)
+	}
+
+	return incidents, rows.Err()
+}
+
+// GetUserIncident возвращает инцидент пользователя вместе с хронологией событий
+func (s *Storage) GetUserIncident(ctx context.Context, userID, incidentID int) (*models.Incident, error) {
+	query := `
+        SELECT ` + incidentColumns + `
+        FROM incidents i
+        JOIN sites s ON s.id = i.site_id
+        WHERE i.id = $1 AND s.user_id = $2
+    `
+
+	var incident models.Incident
+	err := s.db.QueryRow(ctx, query, incidentID, userID).Scan(incidentFields(&incident)...)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get incident: %w", err)
+	}
+
+	events, err := s.getIncidentEvents(ctx, incidentID)
+	if err != nil {
+		return nil, err
+	}
+	incident.Events = events
+
+	notes, err := s.getIncidentNotes(ctx, incidentID)
+	if err != nil {
+		return nil, err
+	}
+	incident.Notes = notes
+
+	return &incident, nil
+}
+
+func (s *Storage) getIncidentEvents(ctx context.Context, incidentID int) ([]models.IncidentEvent, error) {
+	query := `
+        SELECT id, created_at, type, message
+        FROM incident_events
+        WHERE incident_id = $1
+        ORDER BY created_at, id
+    `
+
+	rows, err := s.db.Query(ctx, query, incidentID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get incident events: %w", err)
+	}
+	defer rows.Close()
+
+	var events []models.IncidentEvent
+	for rows.Next() {
+		var event models.IncidentEvent
+		if err := rows.Scan(&event.ID, &event.CreatedAt, &event.Type, &event.Message); err != nil {
+			return nil, fmt.Errorf("failed to scan incident event: %w", err)
+		}
+		events = append(events, event)
+	}
+
+	return events, rows.Err()
+}
+
+func (s *Storage) getIncidentNotes(ctx context.Context, incidentID int) ([]models.IncidentNote, error) {
+	query := `
+        SELECT id, author, text, created_at
+        FROM incident_notes
+        WHERE incident_id = $1
+        ORDER BY created_at, id
+    `
+
+	rows, err := s.db.Query(ctx, query, incidentID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get incident notes: %w", err)
+	}
+	defer rows.Close()
+
+	var notes []models.IncidentNote
+	for rows.Next() {
+		var note models.IncidentNote
+		if err := rows.Scan(&note.ID, &note.Author, &note.Text, &note.CreatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan incident note: %w", err)
+		}
+		notes = append(notes, note)
+	}
+
+	return notes, rows.Err()
+}
+
+// AcknowledgeIncident подтверждает открытый инцидент пользователя. Подтверждение останавливает эскалацию.
+// Возвращает false, если инцидент не найден, уже подтвержден или закрыт.
+func (s *Storage) AcknowledgeIncident(ctx context.Context, userID, incidentID int, by, message string) (bool, error) {
+	query := `
+        WITH acknowledged AS (
+            UPDATE incidents i SET acknowledged_at = $3, acknowledged_by = $4
+            FROM sites s
+            WHERE i.id = $1 AND s.id = i.site_id AND s.user_id = $2
+                AND i.acknowledged_at IS NULL AND i.resolved_at IS NULL
+            RETURNING i.id
+        )
+        INSERT INTO incident_events (incident_id, created_at, type, message)
+        SELECT id, $3, $5, $6 FROM acknowledged
+        RETURNING incident_id
+    `
+
+	var id int
+	err := s.db.QueryRow(ctx, query, incidentID, userID, time.Now(), by, models.IncidentEventAcknowledged, message).Scan(&id)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return false, nil
+		}
+		return false, fmt.Errorf("failed to acknowledge incident: %w", err)
+	}
+
+	return true, nil
+}
+
+// ResolveUserIncident вручную закрывает открытый инцидент пользователя
+// и тем же запросом ставит в очередь уведомление notification (обязательно).
+// Возвращает false, если инцидент не найден или уже закрыт.
+func (s *Storage) ResolveUserIncident(ctx context.Context, userID, incidentID int, message string, notification *models.Notification) (bool, error) {
+	query := `
+        WITH target AS (
+            UPDATE incidents i SET resolved_at = $9
+            FROM sites s
+            WHERE i.id = $10 AND s.id = i.site_id AND s.user_id = $11 AND i.resolved_at IS NULL
+            RETURNING i.site_id AS id, i.id AS incident_id
+        ), logged AS (
+            INSERT INTO incident_events (incident_id, created_at, type, message)
+            SELECT incident_id, $9, $12, $13 FROM target
+        )` + enqueueNotification
+
+	args := append(notificationArgs(notification), time.Now(), incidentID, userID, models.IncidentEventResolved, message)
+	result, err := s.db.Exec(ctx, query, args...)
+	if err != nil {
+		return false, fmt.Errorf("failed to resolve incident: %w", err)
+	}
+
+	return result.RowsAffected() > 0, nil
+}
+
+// CreateIncidentNote добавляет заметку к инциденту
+func (s *Storage) CreateIncidentNote(ctx context.Context, incidentID int, note *models.IncidentNote) error {
+	query := `
+        INSERT INTO incident_notes (incident_id, author, text)
+        VALUES ($1, $2, $3)
+        RETURNING id, created_at
+    `
+
+	err := s.db.QueryRow(ctx, query, incidentID, note.Author, note.Text).Scan(&note.ID, &note.CreatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to create incident note: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,195 @@
+package storage
+
+import (
+	"context"
+	"fmt"
+	"log"
+
+	"github.com/aouxes/uptime-monitor/internal/models"
+	"github.com/jackc/pgx/v5"
+)
+
+const maintenanceWindowColumns = `
+    w.id, w.user_id, w.name, w.type, w.starts_at, w.ends_at, w.weekdays, w.start_time,
+    w.cron, w.duration_minutes, w.timezone, w.created_at,
+    COALESCE((SELECT json_agg(ws.site_id ORDER BY ws.site_id)
+        FROM maintenance_window_sites ws WHERE ws.window_id = w.id), '[]')`
+
+func maintenanceWindowFields(window *models.MaintenanceWindow) []interface{} {
+	return []interface{}{
+		&window.ID,
+		&window.UserID,
+		&window.Name,
+		&window.Type,
+		&window.StartsAt,
+		&window.EndsAt,
+		&window.Weekdays,
+		&window.StartTime,
+		&window.Cron,
+		&window.DurationMinutes,
+		&window.Timezone,
+		&window.CreatedAt,
+		&window.SiteIDs,
+	}
+}
+
+// CreateMaintenanceWindow сохраняет окно обслуживания и привязывает к нему сайты пользователя
+func (s *Storage) CreateMaintenanceWindow(ctx context.Context, window *models.MaintenanceWindow) error {
+	query := `
+        WITH created AS (
+            INSERT INTO maintenance_windows (user_id, name, type, starts_at, ends_at, weekdays,
+                start_time, cron, duration_minutes, timezone)
+            VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
+            RETURNING id, created_at
+        ), linked AS (
+            INSERT INTO maintenance_window_sites (window_id, site_id)
+            SELECT c.id, s.id FROM created c JOIN sites s ON s.id = ANY($11) AND s.user_id = $1
+        )
+        SELECT id, created_at FROM created
+    `
+
+	if window.Weekdays == nil {
+		window.Weekdays = []int{}
+	}
+
+	err := s.db.QueryRow(ctx, query,
+		window.UserID,
+		window.Name,
+		window.Type,
+		window.StartsAt,
+		window.EndsAt,
+		window.Weekdays,
+		window.StartTime,
+		window.Cron,
+		window.DurationMinutes,
+		window.Timezone,
+		window.SiteIDs,
+	).Scan(&window.ID, &window.CreatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to create maintenance window: %w", err)
+	}
+
+	log.Printf("Maintenance window created: ID=%d, UserID=%d, Type=%s", window.ID, window.UserID, window.Type)
+	return nil
+}
+
+// UpdateMaintenanceWindow обновляет окно обслуживания пользователя и заменяет список его сайтов.
+// Возвращает false, если окно не найдено.
+func (s *Storage) UpdateMaintenanceWindow(ctx context.Context, window *models.MaintenanceWindow) (bool, error) {
+	query := `
+        WITH updated AS (
+            UPDATE maintenance_windows SET name = $3, type = $4, starts_at = $5, ends_at = $6,
+                weekdays = $7, start_time = $8, cron = $9, duration_minutes = $10, timezone = $11
+            WHERE id = $1 AND user_id = $2
+            RETURNING id, created_at
+        ), unlinked AS (
+            DELETE FROM maintenance_window_sites
+            WHERE window_id IN (SELECT id FROM updated) AND site_id <> ALL($12)
+        ), linked AS (
+            INSERT INTO maintenance_window_sites (window_id, site_id)
+            SELECT u.id, s.id FROM updated u JOIN sites s ON s.id = ANY($12) AND s.user_id = $2
+            ON CONFLICT DO NOTHING
+        )
+        SELECT created_at FROM updated
+    `
+
+	if window.Weekdays == nil {
+		window.Weekdays = []int{}
+	}
+
+	err := s.db.QueryRow(ctx, query,
+		window.ID,
+		window.UserID,
+		window.Name,
+		window.Type,
+		window.StartsAt,
+		window.EndsAt,
+		window.Weekdays,
+		window.StartTime,
+		window.Cron,
+		window.DurationMinutes,
+		window.Timezone,
+		window.SiteIDs,
+	).Scan(&window.CreatedAt)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return false, nil
+		}
+		return false, fmt.Errorf("failed to update maintenance window: %w", err)
+	}
+
+	return true, nil
+}
+
+// DeleteMaintenanceWindow удаляет окно обслуживания пользователя.
+// Возвращает false, если окно не найдено.
+func (s *Storage) DeleteMaintenanceWindow(ctx context.Context, userID, windowID int) (bool, error) {
+	result, err := s.db.Exec(ctx, `DELETE FROM maintenance_windows WHERE id = $1 AND user_id = $2`, windowID, userID)
+	if err != nil {
+		return false, fmt.Errorf("failed to delete maintenance window: %w", err)
+	}
+
+	return result.RowsAffected() > 0, nil
+}
+
+// GetUserMaintenanceWindows возвращает окна обслуживания пользователя, новые первыми
+func (s *Storage) GetUserMaintenanceWindows(ctx context.Context, userID int) ([]models.MaintenanceWindow, error) {
+	query := `
+        SELECT ` + maintenanceWindowColumns + `
+        FROM maintenance_windows w
+        WHERE w.user_id = $1
+        ORDER BY w.created_at DESC, w.id DESC
+    `
+
+	return s.queryMaintenanceWindows(ctx, query, userID)
+}
+
+// GetUserMaintenanceWindow возвращает окно обслуживания пользователя или nil, если его нет
+func (s *Storage) GetUserMaintenanceWindow(ctx context.Context, userID, windowID int) (*models.MaintenanceWindow, error) {
+	query := `
+        SELECT ` + maintenanceWindowColumns + `
+        FROM maintenance_windows w
+        WHERE w.id = $1 AND w.user_id = $2
+    `
+
+	var window models.MaintenanceWindow
+	err := s.db.QueryRow(ctx, query, windowID, userID).Scan(maintenanceWindowFields(&window)...)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get maintenance window: %w", err)
+	}
+
+	return &window, nil
+}
+
+// GetActiveMaintenanceWindows возвращает окна обслуживания всех сайтов, кроме завершившихся разовых
+func (s *Storage) GetActiveMaintenanceWindows(ctx context.Context) ([]models.MaintenanceWindow, error) {
+	query := `
+        SELECT ` + maintenanceWindowColumns + `
+        FROM maintenance_windows w
+        WHERE w.type <> $1 OR w.ends_at > NOW()
+    `
+
+	return s.queryMaintenanceWindows(ctx, query, models.MaintenanceOnce)
+}
+
+func (s *Storage) queryMaintenanceWindows(ctx context.Context, query string, args ...interface{}) ([]models.MaintenanceWindow, error) {
+	rows, err := s.db.Query(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get maintenance windows: %w", err)
+	}
+	defer rows.Close()
+
+	windows := []models.MaintenanceWindow{}
+	for rows.Next() {
+		var window models.MaintenanceWindow
+		if err := rows.Scan(maintenanceWindowFields(&window)...); err != nil {
+			return nil, fmt.Errorf("failed to scan maintenance window: %w", err)
+		}
+		windows = append(windows, window)
+	}
+
+	return windows, rows.Err()
+}
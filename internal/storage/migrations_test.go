@@ -0,0 +1,82 @@
+package storage
+
+import (
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/aouxes/uptime-monitor/internal/models"
+)
+
+var (
+	sqlComment     = regexp.MustCompile(`--[^\n]*`)
+	createTable    = regexp.MustCompile(`(?is)^CREATE TABLE (?:IF NOT EXISTS )?(\w+)\s*\((.*)\)$`)
+	alterTable     = regexp.MustCompile(`(?is)^ALTER TABLE (?:IF EXISTS )?(\w+)\s+(.*)$`)
+	columnVarchar  = regexp.MustCompile(`(?i)(\w+)\s+VARCHAR\((\d+)\)`)
+	addColumnVar   = regexp.MustCompile(`(?i)ADD COLUMN (?:IF NOT EXISTS )?(\w+)\s+VARCHAR\((\d+)\)`)
+	alterColumnVar = regexp.MustCompile(`(?i)ALTER COLUMN (\w+) TYPE VARCHAR\((\d+)\)`)
+)
+
+// columnWidths возвращает длину VARCHAR столбцов ("таблица.столбец") после применения всех миграций
+func columnWidths(t *testing.T) map[string]int {
+	t.Helper()
+
+	migrations, err := filepath.Glob("../../migrations/*.up.sql")
+	if err != nil || len(migrations) == 0 {
+		t.Fatalf("no migrations found: %v", err)
+	}
+
+	widths := map[string]int{}
+	set := func(table string, match []string) {
+		width, _ := strconv.Atoi(match[2])
+		widths[table+"."+strings.ToLower(match[1])] = width
+	}
+
+	for _, path := range migrations {
+		sql, err := os.ReadFile(path)
+		if err != nil {
+			t.Fatalf("failed to read %s: %v", path, err)
+		}
+
+		for _, statement := range strings.Split(sqlComment.ReplaceAllString(string(sql), ""), ";") {
+			statement = strings.TrimSpace(statement)
+			if m := createTable.FindStringSubmatch(statement); m != nil {
+				for _, column := range columnVarchar.FindAllStringSubmatch(m[2], -1) {
+					set(m[1], column)
+				}
+			} else if m := alterTable.FindStringSubmatch(statement); m != nil {
+				for _, column := range addColumnVar.FindAllStringSubmatch(m[2], -1) {
+					set(m[1], column)
+				}
+				for _, column := range alterColumnVar.FindAllStringSubmatch(m[2], -1) {
+					set(m[1], column)
+				}
+			}
+		}
+	}
+
+	return widths
+}
+
+// Без подключения к базе проверяет, что все статусы помещаются в столбцы, куда они пишутся
+func TestStatusColumnsFitStatuses(t *testing.T) {
+	widths := columnWidths(t)
+	statuses := []string{models.StatusUp, models.StatusDown, models.StatusUnknown, models.StatusMaintenance, models.StatusUnreachable}
+
+	for _, column := range []string{"sites.last_status", "check_results.status", "check_results.confirmed_status", "notification_outbox.old_status", "notification_outbox.new_status"} {
+		width, ok := widths[column]
+		if !ok {
+			t.Errorf("column %s not found in migrations", column)
+			continue
+		}
+
+		for _, status := range statuses {
+			if len(status) > width {
+				t.Errorf("%s is VARCHAR(%d), status %s does not fit", column, width, status)
+			}
+		}
+	}
+}
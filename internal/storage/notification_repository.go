@@ -0,0 +1,199 @@
+package storage
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/aouxes/uptime-monitor/internal/models"
+	"github.com/jackc/pgx/v5"
+)
+
+const notificationColumns = `o.id, o.site_id, s.url, o.event_type, o.old_status, o.new_status, o.reason,
+        o.incident_id, o.cert_not_after, o.cert_days_left, o.escalation_step, o.occurred_at, o.status, o.attempts,
+        o.next_attempt_at, o.last_error, o.delivered, o.created_at, o.sent_at`
+
+// enqueueNotification — запрос постановки уведомления в очередь для сайта из CTE target.
+// Параметры $1-$8 заполняет notificationArgs; без уведомления строка не добавляется.
+const enqueueNotification = `
+        INSERT INTO notification_outbox (site_id, event_type, old_status, new_status, reason, incident_id,
+            cert_not_after, cert_days_left, escalation_step)
+        SELECT id, $1::VARCHAR, $2::VARCHAR, $3::VARCHAR, $4::TEXT, NULLIF($5::INTEGER, 0),
+            $6::TIMESTAMPTZ, $7::INTEGER, $8::INTEGER
+        FROM target
+        WHERE $1::VARCHAR <> ''
+`
+
+// notificationArgs возвращает параметры enqueueNotification
+func notificationArgs(notification *models.Notification) []interface{} {
+	if notification == nil {
+		return []interface{}{"", "", "", "", 0, nil, 0, 0}
+	}
+	return []interface{}{
+		notification.EventType,
+		notification.OldStatus,
+		notification.NewStatus,
+		notification.Reason,
+		notification.IncidentID,
+		notification.CertNotAfter,
+		notification.CertDaysLeft,
+		notification.EscalationStep,
+	}
+}
+
+func scanNotification(row pgx.Row) (*models.Notification, error) {
+	var notification models.Notification
+	var incidentID *int
+
+	err := row.Scan(
+		&notification.ID,
+		&notification.SiteID,
+		&notification.SiteURL,
+		&notification.EventType,
+		&notification.OldStatus,
+		&notification.NewStatus,
+		&notification.Reason,
+		&incidentID,
+		&notification.CertNotAfter,
+		&notification.CertDaysLeft,
+		&notification.EscalationStep,
+		&notification.OccurredAt,
+		&notification.Status,
+		&notification.Attempts,
+		&notification.NextAttemptAt,
+		&notification.LastError,
+		&notification.Delivered,
+		&notification.CreatedAt,
+		&notification.SentAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	if incidentID != nil {
+		notification.IncidentID = *incidentID
+	}
+	return &notification, nil
+}
+
+func scanNotifications(rows pgx.Rows) ([]models.Notification, error) {
+	defer rows.Close()
+
+	var notifications []models.Notification
+	for rows.Next() {
+		notification, err := scanNotification(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan notification: %w", err)
+		}
+		notifications = append(notifications, *notification)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating notifications: %w", err)
+	}
+
+	return notifications, nil
+}
+
+// ClaimNotifications выбирает до limit уведомлений, которым пора уйти, и засчитывает им попытку.
+// Следующая попытка сразу переносится на lease вперед: если процесс упадет во время доставки,
+// уведомление повторится, а параллельные dispatcher'ы его не возьмут.
+func (s *Storage) ClaimNotifications(ctx context.Context, limit int, lease time.Duration) ([]models.Notification, error) {
+	query := `
+        WITH claimed AS (
+            SELECT id FROM notification_outbox
+            WHERE status = 'pending' AND next_attempt_at <= NOW()
+            ORDER BY next_attempt_at, id
+            LIMIT $1
+            FOR UPDATE SKIP LOCKED
+        )
+        UPDATE notification_outbox o
+        SET attempts = o.attempts + 1, next_attempt_at = NOW() + make_interval(secs => $2)
+        FROM claimed, sites s
+        WHERE o.id = claimed.id AND s.id = o.site_id
+        RETURNING ` + notificationColumns
+
+	rows, err := s.db.Query(ctx, query, limit, lease.Seconds())
+	if err != nil {
+		return nil, fmt.Errorf("failed to claim notifications: %w", err)
+	}
+
+	return scanNotifications(rows)
+}
+
+// SaveNotificationAttempt сохраняет итог попытки доставки: статус, следующую попытку,
+// ошибку и каналы, в которые уведомление уже доставлено
+func (s *Storage) SaveNotificationAttempt(ctx context.Context, notification *models.Notification) error {
+	query := `
+        UPDATE notification_outbox
+        SET status = $2, next_attempt_at = $3, last_error = $4, delivered = $5, sent_at = $6
+        WHERE id = $1
+    `
+
+	_, err := s.db.Exec(ctx, query,
+		notification.ID,
+		notification.Status,
+		notification.NextAttemptAt,
+		notification.LastError,
+		notification.Delivered,
+		notification.SentAt,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to save notification attempt: %w", err)
+	}
+
+	return nil
+}
+
+// GetUserFailedNotifications возвращает уведомления пользователя, доставить которые не удалось, новые первыми
+func (s *Storage) GetUserFailedNotifications(ctx context.Context, userID int, limit int) ([]models.Notification, error) {
+	query := `
+        SELECT ` + notificationColumns + `
+        FROM notification_outbox o
+        JOIN sites s ON s.id = o.site_id
+        WHERE s.user_id = $1 AND o.status = 'failed'
+        ORDER BY o.created_at DESC, o.id DESC
+        LIMIT $2
+    `
+
+	rows, err := s.db.Query(ctx, query, userID, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get failed notifications: %w", err)
+	}
+
+	return scanNotifications(rows)
+}
+
+// RetryUserNotification возвращает в очередь уведомление пользователя, доставить которое не удалось.
+// Счетчик попыток сбрасывается, уже доставленные каналы не повторяются.
+// Возвращает nil, если такого уведомления нет.
+func (s *Storage) RetryUserNotification(ctx context.Context, userID int, notificationID int64) (*models.Notification, error) {
+	query := `
+        UPDATE notification_outbox o
+        SET status = 'pending', attempts = 0, next_attempt_at = NOW()
+        FROM sites s
+        WHERE o.id = $2 AND o.status = 'failed' AND s.id = o.site_id AND s.user_id = $1
+        RETURNING ` + notificationColumns
+
+	notification, err := scanNotification(s.db.QueryRow(ctx, query, userID, notificationID))
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to retry notification: %w", err)
+	}
+
+	return notification, nil
+}
+
+// DeleteSentNotifications удаляет из очереди доставленные до before уведомления
+func (s *Storage) DeleteSentNotifications(ctx context.Context, before time.Time) (int64, error) {
+	query := `DELETE FROM notification_outbox WHERE status = 'sent' AND sent_at < $1`
+
+	result, err := s.db.Exec(ctx, query, before)
+	if err != nil {
+		return 0, fmt.Errorf("failed to delete sent notifications: %w", err)
+	}
+
+	return result.RowsAffected(), nil
+}
@@ -12,17 +12,75 @@ import (
 
 func (s *Storage) CreateSite(ctx context.Context, site *models.Site) error {
 	query := `
-        INSERT INTO sites (url, user_id, last_status, last_checked)
-        VALUES ($1, $2, $3, $4)
-        RETURNING id, created_at
+        INSERT INTO sites (url, user_id, last_status, last_checked, interval_seconds,
+            http_method, http_headers, http_body, expected_status_codes, follow_redirects, max_redirects,
+            keyword, keyword_mode, json_assertions, monitor_type, tcp_send, tcp_expect,
+            dns_record_type, dns_resolver, dns_expected, push_token, push_grace_seconds,
+            fail_threshold, recover_threshold, retry_interval_seconds, parent_site_id)
+        VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18, $19, $20,
+            NULLIF($21, ''), $22, $23, $24, $25, NULLIF($26, 0))
+        RETURNING id, last_status, COALESCE(last_checked, created_at), created_at
     `
 
+	if site.IntervalSeconds <= 0 {
+		site.IntervalSeconds = models.DefaultCheckInterval
+	}
+	if site.MonitorType == "" {
+		site.MonitorType = models.MonitorHTTP
+	}
+	if site.HTTPMethod == "" {
+		site.HTTPMethod = models.DefaultHTTPMethod
+	}
+	if site.HTTPHeaders == nil {
+		site.HTTPHeaders = map[string]string{}
+	}
+	if site.ExpectedStatusCodes == "" {
+		site.ExpectedStatusCodes = models.DefaultExpectedStatusCodes
+	}
+	if site.JSONAssertions == nil {
+		site.JSONAssertions = []models.JSONAssertion{}
+	}
+	if site.DNSRecordType == "" {
+		site.DNSRecordType = models.DefaultDNSRecordType
+	}
+	if site.DNSExpected == nil {
+		site.DNSExpected = []string{}
+	}
+	if site.FailThreshold <= 0 {
+		site.FailThreshold = models.DefaultFailThreshold
+	}
+	if site.RecoverThreshold <= 0 {
+		site.RecoverThreshold = models.DefaultRecoverThreshold
+	}
+
 	err := s.db.QueryRow(ctx, query,
 		site.URL,
 		site.UserID,
 		"UNKNOWN",
-		time.Now(),
-	).Scan(&site.ID, &site.CreatedAt)
+		nil,
+		site.IntervalSeconds,
+		site.HTTPMethod,
+		site.HTTPHeaders,
+		site.HTTPBody,
+		site.ExpectedStatusCodes,
+		site.FollowRedirects,
+		site.MaxRedirects,
+		site.Keyword,
+		site.KeywordMode,
+		site.JSONAssertions,
+		site.MonitorType,
+		site.TCPSend,
+		site.TCPExpect,
+		site.DNSRecordType,
+		site.DNSResolver,
+		site.DNSExpected,
+		site.PushToken,
+		site.PushGraceSeconds,
+		site.FailThreshold,
+		site.RecoverThreshold,
+		site.RetryIntervalSeconds,
+		site.ParentSiteID,
+	).Scan(&site.ID, &site.LastStatus, &site.LastChecked, &site.CreatedAt)
 
 	if err != nil {
 		return fmt.Errorf("failed to create site: %w", err)
@@ -35,10 +93,20 @@ func (s *Storage) CreateSite(ctx context.Context, site *models.Site) error {
 
 func (s *Storage) GetUserSites(ctx context.Context, userID int) ([]models.Site, error) {
 	query := `
-        SELECT id, url, user_id, last_status, last_checked, created_at
-        FROM sites 
-        WHERE user_id = $1
-        ORDER BY created_at DESC
+        SELECT ` + siteColumns + `,
+            cr.id, cr.checked_at, cr.status, cr.status_code, cr.response_time_ms,
+            cr.dns_ms, cr.connect_ms, cr.tls_ms, cr.ttfb_ms, cr.error, cr.assertion_failures
+        FROM sites s
+        LEFT JOIN LATERAL (
+            SELECT id, checked_at, status, status_code, response_time_ms,
+                dns_ms, connect_ms, tls_ms, ttfb_ms, error, assertion_failures
+            FROM check_results
+            WHERE site_id = s.id
+            ORDER BY checked_at DESC
+            LIMIT 1
+        ) cr ON true
+        WHERE s.user_id = $1
+        ORDER BY s.created_at DESC
     `
 
 	rows, err := s.db.Query(ctx, query, userID)
@@ -50,39 +118,83 @@ func (s *Storage) GetUserSites(ctx context.Context, userID int) ([]models.Site,
 	var sites []models.Site
 	for rows.Next() {
 		var site models.Site
-		err := rows.Scan(
-			&site.ID,
-			&site.URL,
-			&site.UserID,
-			&site.LastStatus,
-			&site.LastChecked,
-			&site.CreatedAt,
-		)
+		var check lastCheckColumns
+		err := rows.Scan(append(siteFields(&site),
+			&check.ID,
+			&check.CheckedAt,
+			&check.Status,
+			&check.StatusCode,
+			&check.ResponseTimeMs,
+			&check.DNSMs,
+			&check.ConnectMs,
+			&check.TLSMs,
+			&check.TTFBMs,
+			&check.Error,
+			&check.AssertionFailures,
+		)...)
 		if err != nil {
 			return nil, fmt.Errorf("failed to scan site: %w", err)
 		}
+		site.LastCheck = check.result(site.ID)
 		sites = append(sites, site)
 	}
 
 	return sites, nil
 }
 
+// lastCheckColumns — nullable колонки последней проверки из LEFT JOIN
+type lastCheckColumns struct {
+	ID             *int64
+	CheckedAt      *time.Time
+	Status         *string
+	StatusCode     *int
+	ResponseTimeMs *int64
+	DNSMs          *int64
+	ConnectMs      *int64
+	TLSMs          *int64
+	TTFBMs         *int64
+	Error          *string
+
+	AssertionFailures []string
+}
+
+func (c lastCheckColumns) result(siteID int) *models.CheckResult {
+	if c.ID == nil {
+		return nil
+	}
+
+	result := &models.CheckResult{
+		ID:             *c.ID,
+		SiteID:         siteID,
+		CheckedAt:      *c.CheckedAt,
+		Status:         *c.Status,
+		ResponseTimeMs: *c.ResponseTimeMs,
+		DNSMs:          *c.DNSMs,
+		ConnectMs:      *c.ConnectMs,
+		TLSMs:          *c.TLSMs,
+		TTFBMs:         *c.TTFBMs,
+
+		AssertionFailures: c.AssertionFailures,
+	}
+	if c.StatusCode != nil {
+		result.StatusCode = *c.StatusCode
+	}
+	if c.Error != nil {
+		result.Error = *c.Error
+	}
+
+	return result
+}
+
 func (s *Storage) GetSiteByID(ctx context.Context, siteID int) (*models.Site, error) {
 	query := `
-        SELECT id, url, user_id, last_status, last_checked, created_at
-        FROM sites 
-        WHERE id = $1
+        SELECT ` + siteColumns + `
+        FROM sites s
+        WHERE s.id = $1
     `
 
 	var site models.Site
-	err := s.db.QueryRow(ctx, query, siteID).Scan(
-		&site.ID,
-		&site.URL,
-		&site.UserID,
-		&site.LastStatus,
-		&site.LastChecked,
-		&site.CreatedAt,
-	)
+	err := s.db.QueryRow(ctx, query, siteID).Scan(siteFields(&site)...)
 
 	if err != nil {
 		if err == pgx.ErrNoRows {
@@ -94,14 +206,20 @@ func (s *Storage) GetSiteByID(ctx context.Context, siteID int) (*models.Site, er
 	return &site, nil
 }
 
-func (s *Storage) UpdateSiteStatus(ctx context.Context, siteID int, status string) error {
+// UpdateSiteStatus сохраняет статус сайта и счетчики сбоев и успешных проверок подряд.
+// Уведомление о смене статуса (если не nil) ставится в очередь тем же запросом,
+// поэтому оно не теряется и не появляется без сохраненного статуса.
+func (s *Storage) UpdateSiteStatus(ctx context.Context, siteID int, status string, failures, successes int, notification *models.Notification) error {
 	query := `
-        UPDATE sites 
-        SET last_status = $1, last_checked = $2
-        WHERE id = $3
-    `
+        WITH target AS (
+            UPDATE sites
+            SET last_status = $9, last_checked = $10, consecutive_failures = $11, consecutive_successes = $12
+            WHERE id = $13
+            RETURNING id
+        )` + enqueueNotification
 
-	_, err := s.db.Exec(ctx, query, status, time.Now(), siteID)
+	args := append(notificationArgs(notification), status, time.Now(), failures, successes, siteID)
+	_, err := s.db.Exec(ctx, query, args...)
 	if err != nil {
 		return fmt.Errorf("failed to update site status: %w", err)
 	}
@@ -127,9 +245,9 @@ func (s *Storage) DeleteSite(ctx context.Context, siteID int, userID int) error
 
 func (s *Storage) GetAllSites(ctx context.Context) ([]models.Site, error) {
 	query := `
-        SELECT id, url, user_id, last_status, last_checked, created_at
-        FROM sites 
-        ORDER BY last_checked ASC NULLS FIRST
+        SELECT ` + siteColumns + `
+        FROM sites s
+        ORDER BY s.last_checked ASC NULLS FIRST
     `
 
 	rows, err := s.db.Query(ctx, query)
@@ -138,18 +256,202 @@ func (s *Storage) GetAllSites(ctx context.Context) ([]models.Site, error) {
 	}
 	defer rows.Close()
 
+	return scanSites(rows)
+}
+
+// GetDependentSites возвращает все сайты, зависящие от сайта напрямую или через цепочку родителей
+func (s *Storage) GetDependentSites(ctx context.Context, siteID int) ([]models.Site, error) {
+	query := `
+        WITH RECURSIVE dependents AS (
+            SELECT id FROM sites WHERE parent_site_id = $1
+            UNION
+            SELECT c.id FROM sites c JOIN dependents d ON c.parent_site_id = d.id
+        )
+        SELECT ` + siteColumns + `
+        FROM sites s
+        WHERE s.id IN (SELECT id FROM dependents) AND s.id <> $1
+        ORDER BY s.url
+    `
+
+	rows, err := s.db.Query(ctx, query, siteID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get dependent sites: %w", err)
+	}
+	defer rows.Close()
+
+	return scanSites(rows)
+}
+
+// GetSiteByPushToken возвращает push монитор по секретному токену
+func (s *Storage) GetSiteByPushToken(ctx context.Context, token string) (*models.Site, error) {
+	query := `
+        SELECT ` + siteColumns + `
+        FROM sites s
+        WHERE s.push_token = $1 AND s.monitor_type = $2
+    `
+
+	var site models.Site
+	err := s.db.QueryRow(ctx, query, token, models.MonitorPush).Scan(siteFields(&site)...)
+
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get site by push token: %w", err)
+	}
+
+	return &site, nil
+}
+
+// GetPushSites возвращает все push мониторы
+func (s *Storage) GetPushSites(ctx context.Context) ([]models.Site, error) {
+	query := `
+        SELECT ` + siteColumns + `
+        FROM sites s
+        WHERE s.monitor_type = $1
+    `
+
+	rows, err := s.db.Query(ctx, query, models.MonitorPush)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get push sites: %w", err)
+	}
+	defer rows.Close()
+
+	return scanSites(rows)
+}
+
+// UpdateSitePushedAt сохраняет время последнего пинга push монитора
+func (s *Storage) UpdateSitePushedAt(ctx context.Context, siteID int, pushedAt time.Time) error {
+	query := `UPDATE sites SET last_push_at = $1 WHERE id = $2`
+
+	if _, err := s.db.Exec(ctx, query, pushedAt, siteID); err != nil {
+		return fmt.Errorf("failed to update site push time: %w", err)
+	}
+
+	return nil
+}
+
+// UpdateSite обновляет настраиваемые поля сайта пользователя
+func (s *Storage) UpdateSite(ctx context.Context, site *models.Site) error {
+	query := `
+        UPDATE sites
+        SET url = $1, interval_seconds = $2, http_method = $3, http_headers = $4, http_body = $5,
+            expected_status_codes = $6, follow_redirects = $7, max_redirects = $8,
+            keyword = $9, keyword_mode = $10, json_assertions = $11,
+            monitor_type = $12, tcp_send = $13, tcp_expect = $14,
+            dns_record_type = $15, dns_resolver = $16, dns_expected = $17,
+            push_token = NULLIF($18, ''), push_grace_seconds = $19,
+            fail_threshold = $20, recover_threshold = $21, retry_interval_seconds = $22,
+            parent_site_id = NULLIF($23, 0)
+        WHERE id = $24 AND user_id = $25
+    `
+
+	if site.HTTPHeaders == nil {
+		site.HTTPHeaders = map[string]string{}
+	}
+	if site.JSONAssertions == nil {
+		site.JSONAssertions = []models.JSONAssertion{}
+	}
+	if site.DNSExpected == nil {
+		site.DNSExpected = []string{}
+	}
+
+	result, err := s.db.Exec(ctx, query,
+		site.URL,
+		site.IntervalSeconds,
+		site.HTTPMethod,
+		site.HTTPHeaders,
+		site.HTTPBody,
+		site.ExpectedStatusCodes,
+		site.FollowRedirects,
+		site.MaxRedirects,
+		site.Keyword,
+		site.KeywordMode,
+		site.JSONAssertions,
+		site.MonitorType,
+		site.TCPSend,
+		site.TCPExpect,
+		site.DNSRecordType,
+		site.DNSResolver,
+		site.DNSExpected,
+		site.PushToken,
+		site.PushGraceSeconds,
+		site.FailThreshold,
+		site.RecoverThreshold,
+		site.RetryIntervalSeconds,
+		site.ParentSiteID,
+		site.ID,
+		site.UserID,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to update site: %w", err)
+	}
+
+	if result.RowsAffected() == 0 {
+		return fmt.Errorf("site not found or access denied")
+	}
+
+	log.Printf("Site updated: ID=%d, URL=%s, Interval=%ds", site.ID, site.URL, site.IntervalSeconds)
+	return nil
+}
+
+// siteColumns — колонки сайта в порядке siteFields (таблица sites должна иметь алиас s)
+const siteColumns = `s.id, s.url, s.user_id, COALESCE(s.last_status, 'UNKNOWN'),
+            COALESCE(s.last_checked, s.created_at), s.interval_seconds, s.created_at,
+            s.http_method, s.http_headers, s.http_body, s.expected_status_codes,
+            s.follow_redirects, s.max_redirects, s.keyword, s.keyword_mode,
+            s.json_assertions, s.monitor_type, s.tcp_send, s.tcp_expect,
+            s.dns_record_type, s.dns_resolver, s.dns_expected,
+            COALESCE(s.push_token, ''), s.push_grace_seconds, s.last_push_at,
+            s.fail_threshold, s.recover_threshold, s.retry_interval_seconds,
+            s.consecutive_failures, s.consecutive_successes, COALESCE(s.parent_site_id, 0),
+            COALESCE((SELECT json_agg(channel_id ORDER BY channel_id)
+                FROM site_notification_channels WHERE site_id = s.id), '[]'),
+            (SELECT not_after FROM site_certificates WHERE site_id = s.id)`
+
+func siteFields(site *models.Site) []interface{} {
+	return []interface{}{
+		&site.ID,
+		&site.URL,
+		&site.UserID,
+		&site.LastStatus,
+		&site.LastChecked,
+		&site.IntervalSeconds,
+		&site.CreatedAt,
+		&site.HTTPMethod,
+		&site.HTTPHeaders,
+		&site.HTTPBody,
+		&site.ExpectedStatusCodes,
+		&site.FollowRedirects,
+		&site.MaxRedirects,
+		&site.Keyword,
+		&site.KeywordMode,
+		&site.JSONAssertions,
+		&site.MonitorType,
+		&site.TCPSend,
+		&site.TCPExpect,
+		&site.DNSRecordType,
+		&site.DNSResolver,
+		&site.DNSExpected,
+		&site.PushToken,
+		&site.PushGraceSeconds,
+		&site.LastPushAt,
+		&site.FailThreshold,
+		&site.RecoverThreshold,
+		&site.RetryIntervalSeconds,
+		&site.ConsecutiveFailures,
+		&site.ConsecutiveSuccesses,
+		&site.ParentSiteID,
+		&site.NotificationChannelIDs,
+		&site.CertExpiresAt,
+	}
+}
+
+func scanSites(rows pgx.Rows) ([]models.Site, error) {
 	var sites []models.Site
 	for rows.Next() {
 		var site models.Site
-		err := rows.Scan(
-			&site.ID,
-			&site.URL,
-			&site.UserID,
-			&site.LastStatus,
-			&site.LastChecked,
-			&site.CreatedAt,
-		)
-		if err != nil {
+		if err := rows.Scan(siteFields(&site)...); err != nil {
 			return nil, fmt.Errorf("failed to scan site: %w", err)
 		}
 		sites = append(sites, site)
@@ -0,0 +1,103 @@
+package storage
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/aouxes/uptime-monitor/internal/models"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// testStorage подключается к базе из TEST_DATABASE_URL и применяет миграции в отдельной схеме,
+// которая удаляется после теста. Без TEST_DATABASE_URL тест пропускается.
+func testStorage(t *testing.T) *Storage {
+	t.Helper()
+
+	url := os.Getenv("TEST_DATABASE_URL")
+	if url == "" {
+		t.Skip("TEST_DATABASE_URL is not set")
+	}
+
+	ctx := context.Background()
+	schema := fmt.Sprintf("test_%d", time.Now().UnixNano())
+
+	admin, err := pgxpool.New(ctx, url)
+	if err != nil {
+		t.Fatalf("failed to connect: %v", err)
+	}
+	t.Cleanup(admin.Close)
+
+	if _, err := admin.Exec(ctx, "CREATE SCHEMA "+schema); err != nil {
+		t.Fatalf("failed to create schema: %v", err)
+	}
+	t.Cleanup(func() {
+		admin.Exec(context.Background(), "DROP SCHEMA "+schema+" CASCADE")
+	})
+
+	config, err := pgxpool.ParseConfig(url)
+	if err != nil {
+		t.Fatalf("failed to parse TEST_DATABASE_URL: %v", err)
+	}
+	config.ConnConfig.RuntimeParams["search_path"] = schema
+
+	db, err := pgxpool.NewWithConfig(ctx, config)
+	if err != nil {
+		t.Fatalf("failed to connect: %v", err)
+	}
+	t.Cleanup(db.Close)
+
+	migrations, err := filepath.Glob("../../migrations/*.up.sql")
+	if err != nil || len(migrations) == 0 {
+		t.Fatalf("no migrations found: %v", err)
+	}
+	for _, path := range migrations {
+		sql, err := os.ReadFile(path)
+		if err != nil {
+			t.Fatalf("failed to read %s: %v", path, err)
+		}
+		if _, err := db.Exec(ctx, string(sql)); err != nil {
+			t.Fatalf("failed to apply %s: %v", filepath.Base(path), err)
+		}
+	}
+
+	return &Storage{db: db}
+}
+
+func TestSiteStatusValues(t *testing.T) {
+	s := testStorage(t)
+	ctx := context.Background()
+
+	user := &models.User{Username: "status", Email: "status@example.com", PasswordHash: "hash"}
+	if err := s.CreateUser(ctx, user); err != nil {
+		t.Fatal(err)
+	}
+
+	site := &models.Site{URL: "https://example.com", UserID: user.ID}
+	if err := s.CreateSite(ctx, site); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, status := range []string{"UP", "DOWN", "MAINTENANCE", "UNREACHABLE"} {
+		result := &models.CheckResult{SiteID: site.ID, Status: status}
+		if err := s.CreateCheckResult(ctx, result); err != nil {
+			t.Errorf("CreateCheckResult(%s): %v", status, err)
+		}
+
+		if err := s.UpdateSiteStatus(ctx, site.ID, status, 0, 0, nil); err != nil {
+			t.Errorf("UpdateSiteStatus(%s): %v", status, err)
+			continue
+		}
+
+		saved, err := s.GetSiteByID(ctx, site.ID)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if saved.LastStatus != status {
+			t.Errorf("last_status = %q, want %q", saved.LastStatus, status)
+		}
+	}
+}
@@ -4,12 +4,15 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"html"
 	"io"
 	"log"
 	"net/http"
+	"strconv"
 	"strings"
 	"time"
 
+	"github.com/aouxes/uptime-monitor/internal/models"
 	"github.com/aouxes/uptime-monitor/internal/storage"
 )
 
@@ -37,6 +40,23 @@ type Update struct {
 		Text string `json:"text"`
 		Date int64  `json:"date"`
 	} `json:"message"`
+	CallbackQuery *CallbackQuery `json:"callback_query,omitempty"`
+}
+
+// CallbackQuery — нажатие inline кнопки под сообщением бота
+type CallbackQuery struct {
+	ID   string `json:"id"`
+	From struct {
+		ID       int64  `json:"id"`
+		Username string `json:"username"`
+	} `json:"from"`
+	Message *struct {
+		MessageID int `json:"message_id"`
+		Chat      struct {
+			ID int64 `json:"id"`
+		} `json:"chat"`
+	} `json:"message,omitempty"`
+	Data string `json:"data"`
 }
 
 type SendMessageRequest struct {
@@ -123,7 +143,7 @@ func (b *Bot) Start(ctx context.Context) error {
 	// Проверяем токен, отправив тестовый запрос
 	if err := b.testToken(); err != nil {
 		log.Printf("Telegram token validation failed: %v", err)
-		log.Printf("Bot will start in offline mode - notifications will be queued")
+		log.Printf("Bot will start in offline mode - notifications stay in the outbox and are retried")
 		// Не останавливаем бота, просто работаем в ограниченном режиме
 	} else {
 		log.Printf("Telegram token validated successfully")
@@ -208,6 +228,10 @@ func (b *Bot) getUpdates(offset int) ([]Update, error) {
 }
 
 func (b *Bot) handleUpdate(ctx context.Context, update Update) error {
+	if update.CallbackQuery != nil {
+		return b.handleCallbackQuery(ctx, update.CallbackQuery)
+	}
+
 	if update.Message.Text == "" {
 		return nil
 	}
@@ -226,6 +250,8 @@ func (b *Bot) handleUpdate(ctx context.Context, update Update) error {
 			"/link <code>ваш_код</code> - Связать аккаунт\n"+
 			"/unlink - Отвязать аккаунт\n"+
 			"/status - Проверить статус связи\n"+
+			"/maintenance <code>минуты</code> - Начать обслуживание\n"+
+			"/ack [<code>инцидент</code>] - Подтвердить инцидент\n"+
 			"/help - Показать справку")
 
 	case strings.HasPrefix(text, "/link "):
@@ -238,12 +264,25 @@ func (b *Bot) handleUpdate(ctx context.Context, update Update) error {
 	case text == "/status":
 		return b.handleStatusCommand(ctx, chatID)
 
+	case text == "/ack" || strings.HasPrefix(text, "/ack "):
+		args := strings.Fields(strings.TrimPrefix(text, "/ack"))
+		return b.handleAckCommand(ctx, chatID, username, args)
+
+	case text == "/maintenance" || strings.HasPrefix(text, "/maintenance "):
+		args := strings.Fields(strings.TrimPrefix(text, "/maintenance"))
+		return b.handleMaintenanceCommand(ctx, chatID, args)
+
 	case text == "/help":
 		return b.sendMessage(chatID, "📖 <b>Справка по командам</b>\n\n"+
 			"/link <code>код</code> - Связать ваш аккаунт с ботом\n"+
 			"   Получите код в веб-интерфейсе в разделе настроек\n\n"+
 			"/unlink - Отвязать аккаунт от бота\n\n"+
 			"/status - Проверить, связан ли ваш аккаунт\n\n"+
+			"/maintenance <code>минуты</code> [<code>сайт</code> ...] - Начать обслуживание сейчас\n"+
+			"   Уведомления по сайтам не отправляются до конца окна.\n"+
+			"   Сайты задаются ID или URL, без них — все ваши сайты\n\n"+
+			"/ack [<code>инцидент</code>] - Подтвердить инцидент и остановить эскалацию\n"+
+			"   Без номера подтверждаются все открытые инциденты ваших сайтов\n\n"+
 			"/help - Показать эту справку")
 
 	default:
@@ -328,21 +367,242 @@ func (b *Bot) handleStatusCommand(ctx context.Context, chatID int64) error {
 		user.Username, user.Email, user.CreatedAt.Format("02.01.2006")))
 }
 
+// handleCallbackQuery обрабатывает кнопку "Acknowledge" под уведомлением о падении сайта
+func (b *Bot) handleCallbackQuery(ctx context.Context, query *CallbackQuery) error {
+	log.Printf("Received callback from %s: %s", query.From.Username, query.Data)
+
+	if query.Message == nil || !strings.HasPrefix(query.Data, ackCallbackPrefix) {
+		return b.answerCallbackQuery(query.ID, "❓ Неизвестное действие")
+	}
+
+	incidentID, err := strconv.Atoi(strings.TrimPrefix(query.Data, ackCallbackPrefix))
+	if err != nil || incidentID <= 0 {
+		return b.answerCallbackQuery(query.ID, "❓ Неизвестное действие")
+	}
+
+	chatID := query.Message.Chat.ID
+	user, err := b.storage.GetUserByTelegramChatID(ctx, chatID)
+	if err != nil {
+		log.Printf("Failed to get user by telegram chat ID: %v", err)
+		return b.answerCallbackQuery(query.ID, "❌ Ошибка. Попробуйте позже.")
+	}
+
+	if user == nil {
+		return b.answerCallbackQuery(query.ID, "❌ Ваш аккаунт не связан с ботом.")
+	}
+
+	by := user.Username
+	if query.From.Username != "" {
+		by = "@" + query.From.Username
+	}
+
+	acknowledged, err := b.storage.AcknowledgeIncident(ctx, user.ID, incidentID, by, "Acknowledged via Telegram by "+by)
+	if err != nil {
+		log.Printf("Failed to acknowledge incident %d: %v", incidentID, err)
+		return b.answerCallbackQuery(query.ID, "❌ Ошибка. Попробуйте позже.")
+	}
+
+	// Кнопка больше не нужна: инцидент подтвержден сейчас или был подтвержден/закрыт ранее
+	if err := b.removeReplyMarkup(chatID, query.Message.MessageID); err != nil {
+		log.Printf("Failed to remove reply markup: %v", err)
+	}
+
+	if !acknowledged {
+		return b.answerCallbackQuery(query.ID, "ℹ️ Инцидент уже подтвержден или закрыт")
+	}
+
+	log.Printf("Incident %d acknowledged via Telegram by %s", incidentID, by)
+	return b.answerCallbackQuery(query.ID, "✅ Инцидент подтвержден")
+}
+
+func (b *Bot) answerCallbackQuery(queryID, text string) error {
+	return b.call("answerCallbackQuery", map[string]interface{}{
+		"callback_query_id": queryID,
+		"text":              text,
+	})
+}
+
+func (b *Bot) removeReplyMarkup(chatID int64, messageID int) error {
+	return b.call("editMessageReplyMarkup", map[string]interface{}{
+		"chat_id":      chatID,
+		"message_id":   messageID,
+		"reply_markup": InlineKeyboardMarkup{InlineKeyboard: [][]InlineKeyboardButton{}},
+	})
+}
+
+// handleAckCommand подтверждает инцидент args[0], а без аргументов — все открытые
+// неподтвержденные инциденты сайтов пользователя. Подтверждение останавливает эскалацию.
+func (b *Bot) handleAckCommand(ctx context.Context, chatID int64, from string, args []string) error {
+	usage := "Использование: /ack [<code>инцидент</code>]\n" +
+		"Например: /ack 12"
+
+	user, err := b.storage.GetUserByTelegramChatID(ctx, chatID)
+	if err != nil {
+		log.Printf("Failed to get user by telegram chat ID: %v", err)
+		return b.sendMessage(chatID, "❌ Ошибка при подтверждении инцидента. Попробуйте позже.")
+	}
+
+	if user == nil {
+		return b.sendMessage(chatID, "❌ Ваш аккаунт не связан с ботом.")
+	}
+
+	by := user.Username
+	if from != "" {
+		by = "@" + from
+	}
+
+	var incidentIDs []int
+	switch len(args) {
+	case 0:
+		incidents, err := b.storage.GetUserIncidents(ctx, user.ID, models.IncidentFilter{Status: models.IncidentStatusOpen, Limit: 100})
+		if err != nil {
+			log.Printf("Failed to get open incidents: %v", err)
+			return b.sendMessage(chatID, "❌ Ошибка при подтверждении инцидента. Попробуйте позже.")
+		}
+		for _, incident := range incidents {
+			if incident.AcknowledgedAt == nil {
+				incidentIDs = append(incidentIDs, incident.ID)
+			}
+		}
+		if len(incidentIDs) == 0 {
+			return b.sendMessage(chatID, "ℹ️ Нет неподтвержденных открытых инцидентов")
+		}
+	case 1:
+		incidentID, err := strconv.Atoi(strings.TrimPrefix(args[0], "#"))
+		if err != nil || incidentID <= 0 {
+			return b.sendMessage(chatID, "❌ Неверный номер инцидента.\n"+usage)
+		}
+		incidentIDs = []int{incidentID}
+	default:
+		return b.sendMessage(chatID, usage)
+	}
+
+	var acknowledged []string
+	for _, incidentID := range incidentIDs {
+		ok, err := b.storage.AcknowledgeIncident(ctx, user.ID, incidentID, by, "Acknowledged via Telegram by "+by)
+		if err != nil {
+			log.Printf("Failed to acknowledge incident %d: %v", incidentID, err)
+			return b.sendMessage(chatID, "❌ Ошибка при подтверждении инцидента. Попробуйте позже.")
+		}
+		if ok {
+			log.Printf("Incident %d acknowledged via Telegram by %s", incidentID, by)
+			acknowledged = append(acknowledged, fmt.Sprintf("#%d", incidentID))
+		}
+	}
+
+	if len(acknowledged) == 0 {
+		return b.sendMessage(chatID, "ℹ️ Инцидент не найден, уже подтвержден или закрыт")
+	}
+
+	return b.sendMessage(chatID, "✅ Подтверждено: "+strings.Join(acknowledged, ", ")+"\nЭскалация остановлена.")
+}
+
+// handleMaintenanceCommand начинает разовое окно обслуживания длительностью args[0] минут.
+// Остальные аргументы — ID или URL сайтов; без них окно применяется ко всем сайтам пользователя.
+func (b *Bot) handleMaintenanceCommand(ctx context.Context, chatID int64, args []string) error {
+	usage := "Использование: /maintenance <code>минуты</code> [<code>сайт</code> ...]\n" +
+		"Например: /maintenance 30 https://example.com"
+
+	if len(args) == 0 {
+		return b.sendMessage(chatID, "❌ Укажите длительность обслуживания в минутах.\n"+usage)
+	}
+
+	minutes, err := strconv.Atoi(args[0])
+	if err != nil || minutes < 1 || minutes > models.MaxMaintenanceDurationMinutes {
+		return b.sendMessage(chatID, fmt.Sprintf("❌ Длительность должна быть от 1 до %d минут.\n%s",
+			models.MaxMaintenanceDurationMinutes, usage))
+	}
+
+	user, err := b.storage.GetUserByTelegramChatID(ctx, chatID)
+	if err != nil {
+		log.Printf("Failed to get user by telegram chat ID: %v", err)
+		return b.sendMessage(chatID, "❌ Ошибка при создании окна обслуживания. Попробуйте позже.")
+	}
+
+	if user == nil {
+		return b.sendMessage(chatID, "❌ Ваш аккаунт не связан с ботом.")
+	}
+
+	sites, err := b.storage.GetUserSites(ctx, user.ID)
+	if err != nil {
+		log.Printf("Failed to get user sites: %v", err)
+		return b.sendMessage(chatID, "❌ Ошибка при создании окна обслуживания. Попробуйте позже.")
+	}
+
+	var selected []models.Site
+	if len(args) == 1 {
+		selected = sites
+	}
+	for _, arg := range args[1:] {
+		site := findSite(sites, arg)
+		if site == nil {
+			return b.sendMessage(chatID, fmt.Sprintf("❌ Сайт <code>%s</code> не найден.", html.EscapeString(arg)))
+		}
+		selected = append(selected, *site)
+	}
+
+	if len(selected) == 0 {
+		return b.sendMessage(chatID, "❌ У вас нет сайтов для обслуживания.")
+	}
+
+	now := time.Now()
+	ends := now.Add(time.Duration(minutes) * time.Minute)
+	window := &models.MaintenanceWindow{
+		UserID:   user.ID,
+		Name:     "Telegram",
+		Type:     models.MaintenanceOnce,
+		StartsAt: &now,
+		EndsAt:   &ends,
+		Timezone: "UTC",
+	}
+
+	var urls []string
+	for _, site := range selected {
+		window.SiteIDs = append(window.SiteIDs, site.ID)
+		urls = append(urls, html.EscapeString(site.URL))
+	}
+
+	if err := b.storage.CreateMaintenanceWindow(ctx, window); err != nil {
+		log.Printf("Failed to create maintenance window: %v", err)
+		return b.sendMessage(chatID, "❌ Ошибка при создании окна обслуживания. Попробуйте позже.")
+	}
+
+	return b.sendMessage(chatID, fmt.Sprintf("🛠 <b>Обслуживание начато</b>\n\n"+
+		"До: %s UTC\n"+
+		"Сайты:\n%s\n\n"+
+		"Уведомления об этих сайтах не отправляются до конца окна.",
+		ends.UTC().Format("15:04 02.01.2006"), strings.Join(urls, "\n")))
+}
+
+// findSite ищет сайт по ID или URL
+func findSite(sites []models.Site, ref string) *models.Site {
+	id, err := strconv.Atoi(ref)
+	for i := range sites {
+		if (err == nil && sites[i].ID == id) || sites[i].URL == ref {
+			return &sites[i]
+		}
+	}
+	return nil
+}
+
 func (b *Bot) sendMessage(chatID int64, text string) error {
-	message := SendMessageRequest{
+	return b.call("sendMessage", SendMessageRequest{
 		ChatID:    chatID,
 		Text:      text,
 		ParseMode: "HTML",
-	}
+	})
+}
 
-	jsonData, err := json.Marshal(message)
+// call вызывает метод Telegram Bot API с JSON телом
+func (b *Bot) call(method string, payload interface{}) error {
+	jsonData, err := json.Marshal(payload)
 	if err != nil {
-		return fmt.Errorf("failed to marshal message: %w", err)
+		return fmt.Errorf("failed to marshal %s request: %w", method, err)
 	}
 
-	resp, err := b.client.Post(b.apiURL+"/sendMessage", "application/json", strings.NewReader(string(jsonData)))
+	resp, err := b.client.Post(b.apiURL+"/"+method, "application/json", strings.NewReader(string(jsonData)))
 	if err != nil {
-		return fmt.Errorf("failed to send message: %w", err)
+		return fmt.Errorf("failed to call %s: %w", method, err)
 	}
 	defer resp.Body.Close()
 
@@ -358,13 +618,13 @@ func (b *Bot) sendMessage(chatID int64, text string) error {
 	}
 
 	if err := json.Unmarshal(body, &response); err != nil {
-		log.Printf("Failed to decode sendMessage response: %v", err)
+		log.Printf("Failed to decode %s response: %v", method, err)
 		log.Printf("Response body: %s", string(body))
 		return fmt.Errorf("failed to decode response: %w", err)
 	}
 
 	if !response.OK {
-		log.Printf("SendMessage error: %d - %s", response.ErrorCode, response.Description)
+		log.Printf("%s error: %d - %s", method, response.ErrorCode, response.Description)
 		return fmt.Errorf("telegram API error %d: %s", response.ErrorCode, response.Description)
 	}
 
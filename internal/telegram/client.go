@@ -5,9 +5,13 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"html"
 	"log"
 	"net/http"
+	"strings"
 	"time"
+
+	"github.com/aouxes/uptime-monitor/internal/models"
 )
 
 type Client struct {
@@ -17,11 +21,25 @@ type Client struct {
 }
 
 type Message struct {
-	ChatID    int64  `json:"chat_id"`
-	Text      string `json:"text"`
-	ParseMode string `json:"parse_mode,omitempty"`
+	ChatID      int64                 `json:"chat_id"`
+	Text        string                `json:"text"`
+	ParseMode   string                `json:"parse_mode,omitempty"`
+	ReplyMarkup *InlineKeyboardMarkup `json:"reply_markup,omitempty"`
+}
+
+// InlineKeyboardMarkup — кнопки под сообщением
+type InlineKeyboardMarkup struct {
+	InlineKeyboard [][]InlineKeyboardButton `json:"inline_keyboard"`
 }
 
+type InlineKeyboardButton struct {
+	Text         string `json:"text"`
+	CallbackData string `json:"callback_data"`
+}
+
+// ackCallbackPrefix — префикс callback_data кнопки подтверждения инцидента
+const ackCallbackPrefix = "ack:"
+
 type SendMessageResponse struct {
 	OK     bool `json:"ok"`
 	Result struct {
@@ -38,17 +56,20 @@ func NewClient(token string) *Client {
 }
 
 func (c *Client) SendMessage(ctx context.Context, chatID int64, text string) error {
-	if c.token == "" {
-		log.Printf("Telegram token not configured, skipping notification")
-		return nil
-	}
-
-	message := Message{
+	return c.send(ctx, Message{
 		ChatID:    chatID,
 		Text:      text,
 		ParseMode: "HTML",
+	})
+}
+
+func (c *Client) send(ctx context.Context, message Message) error {
+	if c.token == "" {
+		log.Printf("Telegram token not configured, skipping notification")
+		return nil
 	}
 
+	chatID := message.ChatID
 	jsonData, err := json.Marshal(message)
 	if err != nil {
 		return fmt.Errorf("failed to marshal message: %w", err)
@@ -84,7 +105,12 @@ func (c *Client) SendMessage(ctx context.Context, chatID int64, text string) err
 	return nil
 }
 
-func (c *Client) SendSiteStatusNotification(ctx context.Context, chatID int64, siteURL, oldStatus, newStatus string) error {
+// maxListedDependents — сколько зависимых сайтов перечислять в уведомлении
+const maxListedDependents = 10
+
+// SendSiteStatusNotification сообщает о смене статуса сайта, произошедшей в occurredAt. Если при падении
+// открыт инцидент, к сообщению добавляется кнопка его подтверждения. dependents — сайты, зависящие от этого сайта.
+func (c *Client) SendSiteStatusNotification(ctx context.Context, chatID int64, site *models.Site, oldStatus, newStatus, reason string, occurredAt time.Time, incidentID int, dependents []models.Site) error {
 	var emoji string
 	var statusText string
 
@@ -115,9 +141,91 @@ func (c *Client) SendSiteStatusNotification(ctx context.Context, chatID int64, s
 			"⏰ <b>Время:</b> %s UTC",
 		emoji,
 		statusText,
-		siteURL,
+		site.URL,
 		newStatus,
-		time.Now().UTC().Format("15:04:05 02.01.2006"),
+		occurredAt.UTC().Format("15:04:05 02.01.2006"),
+	)
+
+	if reason != "" && newStatus != "UP" {
+		message += fmt.Sprintf("\n💬 <b>Причина:</b> %s", html.EscapeString(reason))
+	}
+
+	if site.CertExpiresAt != nil {
+		message += fmt.Sprintf("\n🔒 <b>Сертификат до:</b> %s", site.CertExpiresAt.UTC().Format("02.01.2006"))
+	}
+
+	if len(dependents) > 0 {
+		var urls []string
+		for i, dependent := range dependents {
+			if i == maxListedDependents {
+				urls = append(urls, fmt.Sprintf("… и еще %d", len(dependents)-maxListedDependents))
+				break
+			}
+			urls = append(urls, html.EscapeString(dependent.URL))
+		}
+		message += fmt.Sprintf("\n🔗 <b>Зависимые сайты (%d):</b>\n%s", len(dependents), strings.Join(urls, "\n"))
+	}
+
+	request := Message{
+		ChatID:    chatID,
+		Text:      message,
+		ParseMode: "HTML",
+	}
+	if newStatus == "DOWN" && incidentID != 0 {
+		request.ReplyMarkup = &InlineKeyboardMarkup{
+			InlineKeyboard: [][]InlineKeyboardButton{{
+				{Text: "✅ Acknowledge", CallbackData: fmt.Sprintf("%s%d", ackCallbackPrefix, incidentID)},
+			}},
+		}
+	}
+
+	return c.send(ctx, request)
+}
+
+// SendEscalationNotification напоминает о неподтвержденном падении сайта на шаге эскалации step,
+// наступившем в occurredAt. К сообщению добавляется кнопка подтверждения инцидента, которая останавливает эскалацию.
+func (c *Client) SendEscalationNotification(ctx context.Context, chatID int64, site *models.Site, reason string, occurredAt time.Time, incidentID, step int) error {
+	message := fmt.Sprintf(
+		"🚨 <b>САЙТ ВСЕ ЕЩЕ НЕДОСТУПЕН</b>\n\n"+
+			"🌐 <b>Сайт:</b> %s\n"+
+			"📊 <b>Статус:</b> DOWN\n"+
+			"⏰ <b>Время:</b> %s UTC\n"+
+			"📈 <b>Эскалация:</b> шаг %d",
+		site.URL,
+		occurredAt.UTC().Format("15:04:05 02.01.2006"),
+		step,
+	)
+
+	if reason != "" {
+		message += fmt.Sprintf("\n💬 <b>Причина:</b> %s", html.EscapeString(reason))
+	}
+
+	request := Message{
+		ChatID:    chatID,
+		Text:      message + "\n\nПодтвердите инцидент, чтобы остановить эскалацию.",
+		ParseMode: "HTML",
+	}
+	if incidentID != 0 {
+		request.ReplyMarkup = &InlineKeyboardMarkup{
+			InlineKeyboard: [][]InlineKeyboardButton{{
+				{Text: "✅ Acknowledge", CallbackData: fmt.Sprintf("%s%d", ackCallbackPrefix, incidentID)},
+			}},
+		}
+	}
+
+	return c.send(ctx, request)
+}
+
+// SendCertificateExpiryNotification предупреждает о скором истечении TLS сертификата
+func (c *Client) SendCertificateExpiryNotification(ctx context.Context, chatID int64, siteURL string, notAfter time.Time, daysLeft int) error {
+	message := fmt.Sprintf(
+		"⚠️ <b>СЕРТИФИКАТ СКОРО ИСТЕКАЕТ</b>\n\n"+
+			"🌐 <b>Сайт:</b> %s\n"+
+			"🔒 <b>Действителен до:</b> %s UTC\n"+
+			"⏳ <b>Осталось дней:</b> %d",
+		siteURL,
+		notAfter.UTC().Format("15:04:05 02.01.2006"),
+		daysLeft,
 	)
 
 	return c.SendMessage(ctx, chatID, message)
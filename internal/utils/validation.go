@@ -1,8 +1,11 @@
 package utils
 
 import (
+	"fmt"
 	"net/mail"
 	"unicode"
+
+	"github.com/aouxes/uptime-monitor/internal/models"
 )
 
 func ValidateUser(username, email, password string) map[string]string {
@@ -38,3 +41,11 @@ func ValidateUser(username, email, password string) map[string]string {
 
 	return errors
 }
+
+// ValidateCheckInterval проверяет интервал проверки сайта в секундах
+func ValidateCheckInterval(seconds int) error {
+	if seconds < models.MinCheckInterval || seconds > models.MaxCheckInterval {
+		return fmt.Errorf("interval_seconds must be between %d and %d", models.MinCheckInterval, models.MaxCheckInterval)
+	}
+	return nil
+}
@@ -56,3 +56,30 @@ func TestValidateUser(t *testing.T) {
 		})
 	}
 }
+
+func TestValidateCheckInterval(t *testing.T) {
+	tests := []struct {
+		name     string
+		seconds  int
+		hasError bool
+	}{
+		{name: "Minimum", seconds: 30, hasError: false},
+		{name: "One hour", seconds: 3600, hasError: false},
+		{name: "Too short", seconds: 5, hasError: true},
+		{name: "Too long", seconds: 7 * 86400, hasError: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateCheckInterval(tt.seconds)
+
+			if tt.hasError && err == nil {
+				t.Error("Expected validation error, but got none")
+			}
+
+			if !tt.hasError && err != nil {
+				t.Errorf("Expected no error, but got: %v", err)
+			}
+		})
+	}
+}
@@ -0,0 +1,11 @@
+CREATE TABLE IF NOT EXISTS check_results (
+    id BIGSERIAL PRIMARY KEY,
+    site_id INTEGER NOT NULL REFERENCES sites(id) ON DELETE CASCADE,
+    checked_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    status VARCHAR(10) NOT NULL, -- 'UP', 'DOWN'
+    status_code INTEGER,
+    response_time_ms INTEGER NOT NULL DEFAULT 0,
+    error TEXT
+);
+
+CREATE INDEX IF NOT EXISTS idx_check_results_site_checked_at ON check_results(site_id, checked_at DESC);
@@ -0,0 +1,5 @@
+ALTER TABLE check_results
+    ADD COLUMN IF NOT EXISTS dns_ms INTEGER NOT NULL DEFAULT 0,
+    ADD COLUMN IF NOT EXISTS connect_ms INTEGER NOT NULL DEFAULT 0,
+    ADD COLUMN IF NOT EXISTS tls_ms INTEGER NOT NULL DEFAULT 0,
+    ADD COLUMN IF NOT EXISTS ttfb_ms INTEGER NOT NULL DEFAULT 0;
@@ -0,0 +1,4 @@
+ALTER TABLE sites
+    ADD COLUMN IF NOT EXISTS interval_seconds INTEGER NOT NULL DEFAULT 300;
+
+CREATE INDEX IF NOT EXISTS idx_sites_last_checked ON sites(last_checked);
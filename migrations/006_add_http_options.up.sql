@@ -0,0 +1,7 @@
+ALTER TABLE sites
+    ADD COLUMN IF NOT EXISTS http_method VARCHAR(10) NOT NULL DEFAULT 'HEAD',
+    ADD COLUMN IF NOT EXISTS http_headers JSONB NOT NULL DEFAULT '{}',
+    ADD COLUMN IF NOT EXISTS http_body TEXT NOT NULL DEFAULT '',
+    ADD COLUMN IF NOT EXISTS expected_status_codes VARCHAR(255) NOT NULL DEFAULT '200-399',
+    ADD COLUMN IF NOT EXISTS follow_redirects BOOLEAN NOT NULL DEFAULT TRUE,
+    ADD COLUMN IF NOT EXISTS max_redirects INTEGER NOT NULL DEFAULT 10;
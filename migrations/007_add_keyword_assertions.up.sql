@@ -0,0 +1,3 @@
+ALTER TABLE sites
+    ADD COLUMN IF NOT EXISTS keyword TEXT NOT NULL DEFAULT '',
+    ADD COLUMN IF NOT EXISTS keyword_mode VARCHAR(20) NOT NULL DEFAULT ''; -- '', 'contains', 'not_contains', 'regex'
@@ -0,0 +1,5 @@
+ALTER TABLE sites
+    ADD COLUMN IF NOT EXISTS json_assertions JSONB NOT NULL DEFAULT '[]';
+
+ALTER TABLE check_results
+    ADD COLUMN IF NOT EXISTS assertion_failures JSONB;
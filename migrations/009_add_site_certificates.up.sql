@@ -0,0 +1,13 @@
+CREATE TABLE IF NOT EXISTS site_certificates (
+    site_id INTEGER PRIMARY KEY REFERENCES sites(id) ON DELETE CASCADE,
+    subject TEXT NOT NULL,
+    issuer TEXT NOT NULL,
+    sans TEXT[] NOT NULL DEFAULT '{}',
+    not_before TIMESTAMP WITH TIME ZONE NOT NULL,
+    not_after TIMESTAMP WITH TIME ZONE NOT NULL,
+    chain JSONB NOT NULL DEFAULT '[]',
+    last_warned_days INTEGER NOT NULL DEFAULT 0, -- последний порог предупреждения об истечении
+    checked_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_site_certificates_not_after ON site_certificates(not_after);
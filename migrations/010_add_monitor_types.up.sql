@@ -0,0 +1,4 @@
+ALTER TABLE sites
+    ADD COLUMN IF NOT EXISTS monitor_type VARCHAR(10) NOT NULL DEFAULT 'http', -- 'http', 'tcp'
+    ADD COLUMN IF NOT EXISTS tcp_send TEXT NOT NULL DEFAULT '',
+    ADD COLUMN IF NOT EXISTS tcp_expect TEXT NOT NULL DEFAULT '';
@@ -0,0 +1,4 @@
+ALTER TABLE sites
+    ADD COLUMN IF NOT EXISTS dns_record_type VARCHAR(10) NOT NULL DEFAULT 'A', -- 'A', 'AAAA', 'CNAME', 'MX', 'TXT', 'NS'
+    ADD COLUMN IF NOT EXISTS dns_resolver VARCHAR(64) NOT NULL DEFAULT '',
+    ADD COLUMN IF NOT EXISTS dns_expected JSONB NOT NULL DEFAULT '[]';
@@ -0,0 +1,6 @@
+ALTER TABLE sites
+    ADD COLUMN IF NOT EXISTS push_token VARCHAR(64),
+    ADD COLUMN IF NOT EXISTS push_grace_seconds INTEGER NOT NULL DEFAULT 60,
+    ADD COLUMN IF NOT EXISTS last_push_at TIMESTAMP WITH TIME ZONE;
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_sites_push_token ON sites(push_token) WHERE push_token IS NOT NULL;
@@ -0,0 +1,6 @@
+ALTER TABLE sites
+    ADD COLUMN IF NOT EXISTS fail_threshold INTEGER NOT NULL DEFAULT 1,
+    ADD COLUMN IF NOT EXISTS recover_threshold INTEGER NOT NULL DEFAULT 1,
+    ADD COLUMN IF NOT EXISTS retry_interval_seconds INTEGER NOT NULL DEFAULT 0, -- 0 - без быстрой повторной проверки
+    ADD COLUMN IF NOT EXISTS consecutive_failures INTEGER NOT NULL DEFAULT 0,
+    ADD COLUMN IF NOT EXISTS consecutive_successes INTEGER NOT NULL DEFAULT 0;
@@ -0,0 +1,24 @@
+CREATE TABLE IF NOT EXISTS incidents (
+    id SERIAL PRIMARY KEY,
+    site_id INTEGER NOT NULL REFERENCES sites(id) ON DELETE CASCADE,
+    started_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    resolved_at TIMESTAMP WITH TIME ZONE,
+    first_error TEXT NOT NULL DEFAULT '',
+    last_error TEXT NOT NULL DEFAULT '',
+    failed_checks INTEGER NOT NULL DEFAULT 1
+);
+
+CREATE INDEX IF NOT EXISTS idx_incidents_site_started ON incidents(site_id, started_at DESC);
+
+-- У сайта может быть только один открытый инцидент
+CREATE UNIQUE INDEX IF NOT EXISTS idx_incidents_open ON incidents(site_id) WHERE resolved_at IS NULL;
+
+CREATE TABLE IF NOT EXISTS incident_events (
+    id BIGSERIAL PRIMARY KEY,
+    incident_id INTEGER NOT NULL REFERENCES incidents(id) ON DELETE CASCADE,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    type VARCHAR(20) NOT NULL, -- 'opened', 'error_changed', 'resolved'
+    message TEXT NOT NULL DEFAULT ''
+);
+
+CREATE INDEX IF NOT EXISTS idx_incident_events_incident ON incident_events(incident_id, created_at);
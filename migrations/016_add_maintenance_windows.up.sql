@@ -0,0 +1,29 @@
+-- Окна обслуживания: во время окна сайты получают статус MAINTENANCE, уведомления не отправляются
+CREATE TABLE IF NOT EXISTS maintenance_windows (
+    id SERIAL PRIMARY KEY,
+    user_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    name VARCHAR(200) NOT NULL DEFAULT '',
+    type VARCHAR(10) NOT NULL, -- 'once', 'weekly', 'cron'
+    starts_at TIMESTAMP WITH TIME ZONE, -- once
+    ends_at TIMESTAMP WITH TIME ZONE, -- once
+    weekdays JSONB NOT NULL DEFAULT '[]', -- weekly: 0 - воскресенье ... 6 - суббота
+    start_time VARCHAR(5) NOT NULL DEFAULT '', -- weekly: 'ЧЧ:ММ'
+    cron VARCHAR(100) NOT NULL DEFAULT '', -- cron: время начала окна
+    duration_minutes INTEGER NOT NULL DEFAULT 0, -- weekly и cron
+    timezone VARCHAR(64) NOT NULL DEFAULT 'UTC',
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_maintenance_windows_user ON maintenance_windows(user_id);
+
+CREATE TABLE IF NOT EXISTS maintenance_window_sites (
+    window_id INTEGER NOT NULL REFERENCES maintenance_windows(id) ON DELETE CASCADE,
+    site_id INTEGER NOT NULL REFERENCES sites(id) ON DELETE CASCADE,
+    PRIMARY KEY (window_id, site_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_maintenance_window_sites_site ON maintenance_window_sites(site_id);
+
+-- MAINTENANCE и UNREACHABLE не помещаются в VARCHAR(10)
+ALTER TABLE sites ALTER COLUMN last_status TYPE VARCHAR(20);
+ALTER TABLE check_results ALTER COLUMN status TYPE VARCHAR(20);
@@ -0,0 +1,5 @@
+-- Родительский монитор: пока он недоступен, сбои дочерних сайтов получают статус UNREACHABLE
+ALTER TABLE sites
+    ADD COLUMN IF NOT EXISTS parent_site_id INTEGER REFERENCES sites(id) ON DELETE SET NULL;
+
+CREATE INDEX IF NOT EXISTS idx_sites_parent ON sites(parent_site_id) WHERE parent_site_id IS NOT NULL;
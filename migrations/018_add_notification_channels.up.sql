@@ -0,0 +1,22 @@
+-- Каналы уведомлений пользователя: тип канала и его настройки в JSON
+CREATE TABLE IF NOT EXISTS notification_channels (
+    id SERIAL PRIMARY KEY,
+    user_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    type VARCHAR(20) NOT NULL,
+    name VARCHAR(100) NOT NULL DEFAULT '',
+    config JSONB NOT NULL DEFAULT '{}',
+    is_default BOOLEAN NOT NULL DEFAULT TRUE, -- срабатывает для сайтов без явной маршрутизации
+    enabled BOOLEAN NOT NULL DEFAULT TRUE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_notification_channels_user ON notification_channels(user_id);
+
+-- Маршрутизация: если у сайта есть записи, уведомления идут только в эти каналы
+CREATE TABLE IF NOT EXISTS site_notification_channels (
+    site_id INTEGER NOT NULL REFERENCES sites(id) ON DELETE CASCADE,
+    channel_id INTEGER NOT NULL REFERENCES notification_channels(id) ON DELETE CASCADE,
+    PRIMARY KEY (site_id, channel_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_site_notification_channels_channel ON site_notification_channels(channel_id);
@@ -0,0 +1,24 @@
+-- Очередь уведомлений: событие записывается вместе со сменой статуса сайта,
+-- а фоновый dispatcher доставляет его с повторами
+CREATE TABLE IF NOT EXISTS notification_outbox (
+    id BIGSERIAL PRIMARY KEY,
+    site_id INTEGER NOT NULL REFERENCES sites(id) ON DELETE CASCADE,
+    event_type VARCHAR(20) NOT NULL,
+    old_status VARCHAR(20) NOT NULL DEFAULT '',
+    new_status VARCHAR(20) NOT NULL DEFAULT '',
+    reason TEXT NOT NULL DEFAULT '',
+    incident_id INTEGER,
+    cert_not_after TIMESTAMP WITH TIME ZONE,
+    cert_days_left INTEGER NOT NULL DEFAULT 0,
+    occurred_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    status VARCHAR(10) NOT NULL DEFAULT 'pending', -- pending, sent или failed
+    attempts INTEGER NOT NULL DEFAULT 0,
+    next_attempt_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    last_error TEXT NOT NULL DEFAULT '',
+    delivered TEXT[] NOT NULL DEFAULT '{}', -- каналы, в которые уведомление уже доставлено
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    sent_at TIMESTAMP WITH TIME ZONE
+);
+
+CREATE INDEX IF NOT EXISTS idx_notification_outbox_pending ON notification_outbox(next_attempt_at) WHERE status = 'pending';
+CREATE INDEX IF NOT EXISTS idx_notification_outbox_site ON notification_outbox(site_id);
@@ -0,0 +1,28 @@
+-- Политики эскалации: шаги повторных уведомлений о неподтвержденном падении
+CREATE TABLE IF NOT EXISTS escalation_policies (
+    id SERIAL PRIMARY KEY,
+    user_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    name VARCHAR(100) NOT NULL DEFAULT '',
+    steps JSONB NOT NULL DEFAULT '[]', -- задержка и получатели каждого шага
+    repeat_steps BOOLEAN NOT NULL DEFAULT FALSE, -- после последнего шага начинать сначала
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_escalation_policies_user ON escalation_policies(user_id);
+
+-- У сайта не больше одной политики эскалации
+CREATE TABLE IF NOT EXISTS escalation_policy_sites (
+    site_id INTEGER PRIMARY KEY REFERENCES sites(id) ON DELETE CASCADE,
+    policy_id INTEGER NOT NULL REFERENCES escalation_policies(id) ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS idx_escalation_policy_sites_policy ON escalation_policy_sites(policy_id);
+
+-- Ход эскалации инцидента: сколько шагов выполнено и когда последний
+ALTER TABLE incidents
+    ADD COLUMN IF NOT EXISTS escalation_step INTEGER NOT NULL DEFAULT 0,
+    ADD COLUMN IF NOT EXISTS escalated_at TIMESTAMP WITH TIME ZONE;
+
+-- Номер шага для уведомлений эскалации в очереди
+ALTER TABLE notification_outbox
+    ADD COLUMN IF NOT EXISTS escalation_step INTEGER NOT NULL DEFAULT 0;
@@ -0,0 +1,6 @@
+-- Подтвержденный статус сайта после проверки (с учетом порогов failure_threshold
+-- и recover_threshold), по нему считается доступность. Для старых проверок
+-- подтвержденный статус неизвестен, поэтому используется статус самой проверки.
+ALTER TABLE check_results ADD COLUMN IF NOT EXISTS confirmed_status VARCHAR(20);
+UPDATE check_results SET confirmed_status = status WHERE confirmed_status IS NULL;
+ALTER TABLE check_results ALTER COLUMN confirmed_status SET NOT NULL;
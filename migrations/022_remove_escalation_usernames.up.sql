@@ -0,0 +1,7 @@
+-- Шаги эскалации больше не уведомляют других пользователей: убираем usernames из сохраненных шагов
+UPDATE escalation_policies p
+SET steps = (
+    SELECT jsonb_agg(step - 'usernames' ORDER BY position)
+    FROM jsonb_array_elements(p.steps) WITH ORDINALITY AS s(step, position)
+)
+WHERE jsonb_path_exists(p.steps, '$[*].usernames');